@@ -68,6 +68,7 @@ func New() *cobra.Command {
 	generator.Flags().Bool("prefer_operational_state", true, "If set to true, state (config false) fields in the YANG schema are preferred over intended config leaves in the generated Go code with compressed schema paths. This flag is only valid for compress_paths=true.")
 	generator.Flags().Bool("use_module_name_as_path_origin", false, "If set to true, the YANG module name will be used as the origin in the generated gNMI paths.")
 	generator.Flags().String("set_path_origin", "", "Change the name of the origin in the generated gNMI paths.")
+	generator.Flags().Bool("include_model_data", false, "If set to true, the ΓModelData variable containing the names and versions of the YANG modules is generated in the schema structs package. It can be compared with the models supported by a device using ygnmi.Capabilities.CheckModels.")
 
 	// TODO(wenovus): Delete these hidden flags before or on v1 release.
 	generator.Flags().Bool("typedef_enum_with_defmod", true, "If set to true, all typedefs of type enumeration or identity will be prefixed with the name of its module of definition instead of its residing module.")
@@ -266,7 +267,7 @@ func generateStructs(modules []string, schemaPath, version, fakeRootName string,
 			GenerateLeafSetters:                 true,
 			ValidateFunctionName:                "Validate",
 			GenerateSimpleUnions:                true,
-			IncludeModelData:                    false,
+			IncludeModelData:                    viper.GetBool("include_model_data"),
 			AppendEnumSuffixForSimpleUnionEnums: true,
 			GenerateOrderedListsAsUnorderedMaps: !viper.GetBool("generate_atomic_lists"),
 		},
@@ -0,0 +1,74 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openconfig/ygnmi/app/ygnmi/cmd"
+)
+
+func TestIncludeModelData(t *testing.T) {
+	tests := []struct {
+		desc          string
+		flags         []string
+		wantModelData bool
+	}{{
+		desc: "default",
+	}, {
+		desc:          "include model data",
+		flags:         []string{"--include_model_data"},
+		wantModelData: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			dir := t.TempDir()
+			args := append([]string{
+				"generator",
+				"--base_package_path=example.com/oc",
+				"--trim_module_prefix=openconfig",
+				"--output_dir=" + dir,
+			}, tt.flags...)
+			args = append(args,
+				"../../../../pathgen/testdata/yang/openconfig-simple.yang",
+				"../../../../pathgen/testdata/yang/openconfig-nested.yang",
+			)
+			root := cmd.New()
+			root.SetArgs(args)
+			if err := root.Execute(); err != nil {
+				t.Fatalf("Execute() returned unexpected error: %v", err)
+			}
+			b, err := os.ReadFile(filepath.Join(dir, "structs-0.go"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := string(b)
+			if gotModelData := strings.Contains(got, "var ΓModelData = []*gpb.ModelData{"); gotModelData != tt.wantModelData {
+				t.Fatalf("generated ΓModelData: got %v, want %v", gotModelData, tt.wantModelData)
+			}
+			if !tt.wantModelData {
+				return
+			}
+			for _, module := range []string{"openconfig-simple", "openconfig-nested"} {
+				if !strings.Contains(got, `Name: "`+module+`"`) {
+					t.Errorf("ΓModelData doesn't contain module %q:\n%s", module, got)
+				}
+			}
+		})
+	}
+}
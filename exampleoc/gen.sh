@@ -22,6 +22,7 @@ go run ../app/ygnmi generator \
   --base_package_path=github.com/openconfig/ygnmi/exampleoc \
   --split_package_paths=/model/a=a,/model/b \
   --split_top_level_packages=false \
+  --include_model_data \
   ../pathgen/testdata/yang/openconfig-simple.yang \
   ../pathgen/testdata/yang/openconfig-withlistval.yang \
   ../pathgen/testdata/yang/openconfig-nested.yang
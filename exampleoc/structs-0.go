@@ -33,6 +33,7 @@ import (
 	"fmt"
 	"reflect"
 
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
 	"github.com/openconfig/goyang/pkg/yang"
 	"github.com/openconfig/ygot/ygot"
 	"github.com/openconfig/ygot/ytypes"
@@ -145,6 +146,28 @@ func Unmarshal(data []byte, destStruct ygot.GoStruct, opts ...ytypes.UnmarshalOp
 	return ytypes.Unmarshal(schema, destStruct, jsonTree, opts...)
 }
 
+// ΓModelData contains the catalogue information corresponding to the modules for
+// which Go code was generated.
+var ΓModelData = []*gpb.ModelData{
+	{
+		Name:         "openconfig-extensions",
+		Organization: "OpenConfig working group",
+		Version:      "0.5.1",
+	},
+	{
+		Name: "openconfig-nested",
+	},
+	{
+		Name: "openconfig-remote",
+	},
+	{
+		Name: "openconfig-simple",
+	},
+	{
+		Name: "openconfig-withlistval",
+	},
+}
+
 // A represents the /openconfig-nested/a YANG schema element.
 type A struct {
 	B *A_B `path:"b" module:"openconfig-nested"`
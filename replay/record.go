@@ -0,0 +1,188 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// Recorder is a gNMI client recording the RPCs made with the client it wraps.
+// The recording is written as the RPCs are made, it can be replayed with NewClient.
+type Recorder struct {
+	client gpb.GNMIClient
+
+	mu    sync.Mutex
+	enc   *json.Encoder
+	calls int
+	// last is the time of the last event of the ongoing calls.
+	last map[int]time.Time
+	err  error
+}
+
+// NewRecorder returns a Recorder of the RPCs made with c, writing the recording to w.
+func NewRecorder(c gpb.GNMIClient, w io.Writer) *Recorder {
+	return &Recorder{
+		client: c,
+		enc:    json.NewEncoder(w),
+		last:   map[int]time.Time{},
+	}
+}
+
+// Err returns the first error writing the recording, if any.
+// RPCs are not affected by the errors writing the recording.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// start returns the identifier of a new call.
+func (r *Recorder) start() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls++
+	return r.calls
+}
+
+// record writes an event of the call with the message or the error.
+// The call is done if the event is the last one of the call.
+func (r *Recorder) record(call int, rpc, kind string, m proto.Message, err error, done bool) {
+	ev := &event{Call: call, RPC: rpc, Kind: kind}
+	if m != nil {
+		b, merr := protojson.Marshal(m)
+		if merr != nil {
+			r.setErr(fmt.Errorf("failed to marshal %s %s: %w", rpc, kind, merr))
+			return
+		}
+		ev.Message = b
+	}
+	if err != nil {
+		s := status.Convert(err)
+		ev.Code, ev.Error = s.Code(), s.Message()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	if last, ok := r.last[call]; ok {
+		ev.Delay = now.Sub(last)
+	}
+	r.last[call] = now
+	if done {
+		delete(r.last, call)
+	}
+	if werr := r.enc.Encode(ev); werr != nil && r.err == nil {
+		r.err = fmt.Errorf("failed to write recording: %w", werr)
+	}
+}
+
+// forget drops the state of a call that ended without a recorded event.
+func (r *Recorder) forget(call int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.last, call)
+}
+
+// setErr sets the error of the recording, unless there already is one.
+func (r *Recorder) setErr(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+// recordUnary records the request and the response of a unary RPC.
+func recordUnary[Req, Resp proto.Message](ctx context.Context, r *Recorder, rpc string, req Req, invoke func(context.Context, Req, ...grpc.CallOption) (Resp, error), opts ...grpc.CallOption) (Resp, error) {
+	call := r.start()
+	r.record(call, rpc, kindRequest, req, nil, false)
+	resp, err := invoke(ctx, req, opts...)
+	if err != nil {
+		r.record(call, rpc, kindError, nil, err, true)
+	} else {
+		r.record(call, rpc, kindResponse, resp, nil, true)
+	}
+	return resp, err
+}
+
+// Capabilities records a Capabilities RPC.
+func (r *Recorder) Capabilities(ctx context.Context, req *gpb.CapabilityRequest, opts ...grpc.CallOption) (*gpb.CapabilityResponse, error) {
+	return recordUnary(ctx, r, rpcCapabilities, req, r.client.Capabilities, opts...)
+}
+
+// Get records a Get RPC.
+func (r *Recorder) Get(ctx context.Context, req *gpb.GetRequest, opts ...grpc.CallOption) (*gpb.GetResponse, error) {
+	return recordUnary(ctx, r, rpcGet, req, r.client.Get, opts...)
+}
+
+// Set records a Set RPC.
+func (r *Recorder) Set(ctx context.Context, req *gpb.SetRequest, opts ...grpc.CallOption) (*gpb.SetResponse, error) {
+	return recordUnary(ctx, r, rpcSet, req, r.client.Set, opts...)
+}
+
+// Subscribe records a Subscribe RPC: the requests sent and the responses received on the stream,
+// until the stream ends. Errors caused by the cancellation of ctx aren't recorded,
+// as they depend on the client rather than on the server.
+func (r *Recorder) Subscribe(ctx context.Context, opts ...grpc.CallOption) (gpb.GNMI_SubscribeClient, error) {
+	sub, err := r.client.Subscribe(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &recordingStream{GNMI_SubscribeClient: sub, ctx: ctx, r: r, call: r.start()}, nil
+}
+
+// recordingStream records the requests and the responses of a Subscribe stream.
+type recordingStream struct {
+	gpb.GNMI_SubscribeClient
+	ctx  context.Context
+	r    *Recorder
+	call int
+}
+
+func (s *recordingStream) Send(req *gpb.SubscribeRequest) error {
+	if err := s.GNMI_SubscribeClient.Send(req); err != nil {
+		return err
+	}
+	s.r.record(s.call, rpcSubscribe, kindRequest, req, nil, false)
+	return nil
+}
+
+func (s *recordingStream) Recv() (*gpb.SubscribeResponse, error) {
+	resp, err := s.GNMI_SubscribeClient.Recv()
+	switch {
+	case err == nil:
+		s.r.record(s.call, rpcSubscribe, kindResponse, resp, nil, false)
+	case errors.Is(err, io.EOF):
+		s.r.record(s.call, rpcSubscribe, kindEnd, nil, nil, true)
+	case s.ctx.Err() == nil:
+		s.r.record(s.call, rpcSubscribe, kindError, nil, err, true)
+	default:
+		s.r.forget(s.call)
+	}
+	return resp, err
+}
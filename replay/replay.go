@@ -0,0 +1,364 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package replay records gNMI sessions and replays them, so that tests using a ygnmi.Client
+// can run offline with the data captured from real devices.
+//
+// A Recorder wraps the gNMI client of a session and writes its Capabilities, Get, Set and Subscribe RPCs
+// as JSON Lines: one event per line, for each request, response, error and end of stream,
+// with the messages in their protojson encoding and the delay since the previous event of the RPC.
+// A Client implements a gNMI client from a recording: the requests are matched with the recorded requests
+// and the recorded responses are replayed with their original delays.
+//
+// Recording a session:
+//
+//	f, err := os.Create("session.jsonl")
+//	...
+//	c, err := ygnmi.NewClient(replay.NewRecorder(gpb.NewGNMIClient(conn), f))
+//
+// Replaying it:
+//
+//	f, err := os.Open("session.jsonl")
+//	...
+//	rc, err := replay.NewClient(f)
+//	...
+//	c, err := ygnmi.NewClient(rc)
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// The RPCs of the recorded events.
+const (
+	rpcCapabilities = "Capabilities"
+	rpcGet          = "Get"
+	rpcSet          = "Set"
+	rpcSubscribe    = "Subscribe"
+)
+
+// The kinds of the recorded events.
+const (
+	kindRequest  = "request"
+	kindResponse = "response"
+	kindError    = "error"
+	// kindEnd is the end of a Subscribe stream by the server.
+	kindEnd = "end"
+)
+
+// event is a line of a recording.
+type event struct {
+	// Call identifies the RPC of the event, RPCs are numbered in the order they were started.
+	Call int    `json:"call"`
+	RPC  string `json:"rpc"`
+	Kind string `json:"kind"`
+	// Delay is the time since the previous event of the RPC, in nanoseconds.
+	Delay time.Duration `json:"delay,omitempty"`
+	// Message is the protojson of the request or the response.
+	Message json.RawMessage `json:"message,omitempty"`
+	// Code and Error are the status of an error.
+	Code  codes.Code `json:"code,omitempty"`
+	Error string     `json:"error,omitempty"`
+
+	msg proto.Message
+}
+
+// newMessage returns an empty message of the RPC and kind of the event.
+func (e *event) newMessage() (proto.Message, error) {
+	switch {
+	case e.RPC == rpcCapabilities && e.Kind == kindRequest:
+		return &gpb.CapabilityRequest{}, nil
+	case e.RPC == rpcCapabilities && e.Kind == kindResponse:
+		return &gpb.CapabilityResponse{}, nil
+	case e.RPC == rpcGet && e.Kind == kindRequest:
+		return &gpb.GetRequest{}, nil
+	case e.RPC == rpcGet && e.Kind == kindResponse:
+		return &gpb.GetResponse{}, nil
+	case e.RPC == rpcSet && e.Kind == kindRequest:
+		return &gpb.SetRequest{}, nil
+	case e.RPC == rpcSet && e.Kind == kindResponse:
+		return &gpb.SetResponse{}, nil
+	case e.RPC == rpcSubscribe && e.Kind == kindRequest:
+		return &gpb.SubscribeRequest{}, nil
+	case e.RPC == rpcSubscribe && e.Kind == kindResponse:
+		return &gpb.SubscribeResponse{}, nil
+	}
+	return nil, fmt.Errorf("unexpected %s event of RPC %q", e.Kind, e.RPC)
+}
+
+// err returns the status error of an error event.
+func (e *event) err() error {
+	return status.Error(e.Code, e.Error)
+}
+
+// call is a recorded RPC.
+type call struct {
+	rpc     string
+	events  []*event
+	claimed bool
+}
+
+// Client is a gNMI client replaying a recording made by a Recorder.
+// Each RPC is matched with the first recorded RPC that isn't replayed yet and has the same first request,
+// so the RPCs of a recording can be replayed in a different order.
+// Requests without a match fail with a NotFound error.
+type Client struct {
+	mu    sync.Mutex
+	calls []*call
+}
+
+// NewClient returns a Client replaying the recording read from r.
+func NewClient(r io.Reader) (*Client, error) {
+	c := &Client{}
+	calls := map[int]*call{}
+	dec := json.NewDecoder(r)
+	for line := 1; ; line++ {
+		ev := &event{}
+		if err := dec.Decode(ev); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to decode event %d: %w", line, err)
+		}
+		if ev.Kind == kindRequest || ev.Kind == kindResponse {
+			m, err := ev.newMessage()
+			if err != nil {
+				return nil, fmt.Errorf("event %d: %w", line, err)
+			}
+			if err := protojson.Unmarshal(ev.Message, m); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal the message of event %d: %w", line, err)
+			}
+			ev.msg = m
+		}
+		cl, ok := calls[ev.Call]
+		if !ok {
+			if ev.Kind != kindRequest {
+				return nil, fmt.Errorf("event %d: call %d doesn't start with a request", line, ev.Call)
+			}
+			cl = &call{rpc: ev.RPC}
+			calls[ev.Call] = cl
+			c.calls = append(c.calls, cl)
+		}
+		cl.events = append(cl.events, ev)
+	}
+	return c, nil
+}
+
+// claim returns the first unclaimed call of the RPC whose first request is req.
+func (c *Client) claim(rpc string, req proto.Message) (*call, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, cl := range c.calls {
+		if !cl.claimed && cl.rpc == rpc && proto.Equal(cl.events[0].msg, req) {
+			cl.claimed = true
+			return cl, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "no recorded %s call matches request %v", rpc, req)
+}
+
+// wait waits for the delay, or until ctx is done.
+func wait(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		if err := ctx.Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// replayUnary replays the response of the recorded unary RPC matching req.
+func replayUnary[Resp proto.Message](ctx context.Context, c *Client, rpc string, req proto.Message) (Resp, error) {
+	var zero Resp
+	cl, err := c.claim(rpc, req)
+	if err != nil {
+		return zero, err
+	}
+	if len(cl.events) < 2 {
+		return zero, status.Errorf(codes.Unavailable, "recorded %s call has no response", rpc)
+	}
+	ev := cl.events[1]
+	if err := wait(ctx, ev.Delay); err != nil {
+		return zero, err
+	}
+	if ev.Kind == kindError {
+		return zero, ev.err()
+	}
+	resp, ok := ev.msg.(Resp)
+	if !ok {
+		return zero, status.Errorf(codes.Internal, "recorded %s call has unexpected %s event", rpc, ev.Kind)
+	}
+	return proto.Clone(resp).(Resp), nil
+}
+
+// Capabilities replays a Capabilities RPC.
+func (c *Client) Capabilities(ctx context.Context, req *gpb.CapabilityRequest, _ ...grpc.CallOption) (*gpb.CapabilityResponse, error) {
+	return replayUnary[*gpb.CapabilityResponse](ctx, c, rpcCapabilities, req)
+}
+
+// Get replays a Get RPC.
+func (c *Client) Get(ctx context.Context, req *gpb.GetRequest, _ ...grpc.CallOption) (*gpb.GetResponse, error) {
+	return replayUnary[*gpb.GetResponse](ctx, c, rpcGet, req)
+}
+
+// Set replays a Set RPC.
+func (c *Client) Set(ctx context.Context, req *gpb.SetRequest, _ ...grpc.CallOption) (*gpb.SetResponse, error) {
+	return replayUnary[*gpb.SetResponse](ctx, c, rpcSet, req)
+}
+
+// Subscribe replays a Subscribe RPC. The stream is matched with a recorded stream on its first request,
+// the following requests, such as polls, must be equal to the recorded ones.
+// Once the recorded responses run out, the stream blocks until ctx is done.
+func (c *Client) Subscribe(ctx context.Context, _ ...grpc.CallOption) (gpb.GNMI_SubscribeClient, error) {
+	return &replayStream{ctx: ctx, client: c, sent: make(chan struct{}, 1)}, nil
+}
+
+// replayStream replays the responses of a recorded Subscribe stream.
+type replayStream struct {
+	ctx    context.Context
+	client *Client
+
+	mu       sync.Mutex
+	requests []*gpb.SubscribeRequest
+	// sent is signaled when a request is sent.
+	sent chan struct{}
+
+	// The state of the replay, only used by Recv.
+	call *call
+	pos  int
+	last time.Time
+	// err is the error of the end of the stream.
+	err error
+}
+
+func (s *replayStream) Send(req *gpb.SubscribeRequest) error {
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	s.mu.Unlock()
+	select {
+	case s.sent <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// nextRequest returns the next request sent on the stream, waiting for it if needed.
+func (s *replayStream) nextRequest() (*gpb.SubscribeRequest, error) {
+	for {
+		s.mu.Lock()
+		if len(s.requests) > 0 {
+			req := s.requests[0]
+			s.requests = s.requests[1:]
+			s.mu.Unlock()
+			return req, nil
+		}
+		s.mu.Unlock()
+		select {
+		case <-s.ctx.Done():
+			return nil, status.FromContextError(s.ctx.Err()).Err()
+		case <-s.sent:
+		}
+	}
+}
+
+func (s *replayStream) Recv() (*gpb.SubscribeResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	for {
+		if s.call == nil || s.pos < len(s.call.events) && s.call.events[s.pos].Kind == kindRequest {
+			req, err := s.nextRequest()
+			if err != nil {
+				return nil, err
+			}
+			if s.call == nil {
+				if s.call, err = s.client.claim(rpcSubscribe, req); err != nil {
+					return nil, err
+				}
+			} else if want := s.call.events[s.pos].msg; !proto.Equal(want, req) {
+				return nil, status.Errorf(codes.FailedPrecondition, "request %v doesn't match the recorded request %v", req, want)
+			}
+			s.pos++
+			s.last = time.Now()
+			continue
+		}
+		if s.pos == len(s.call.events) {
+			<-s.ctx.Done()
+			return nil, status.FromContextError(s.ctx.Err()).Err()
+		}
+		ev := s.call.events[s.pos]
+		s.pos++
+		if err := wait(s.ctx, time.Until(s.last.Add(ev.Delay))); err != nil {
+			return nil, err
+		}
+		s.last = time.Now()
+		switch ev.Kind {
+		case kindResponse:
+			return proto.Clone(ev.msg).(*gpb.SubscribeResponse), nil
+		case kindEnd:
+			s.err = io.EOF
+		default:
+			s.err = ev.err()
+		}
+		return nil, s.err
+	}
+}
+
+func (s *replayStream) Header() (metadata.MD, error) { return metadata.MD{}, nil }
+func (s *replayStream) Trailer() metadata.MD         { return metadata.MD{} }
+func (s *replayStream) CloseSend() error             { return nil }
+func (s *replayStream) Context() context.Context     { return s.ctx }
+
+func (s *replayStream) SendMsg(m any) error {
+	req, ok := m.(*gpb.SubscribeRequest)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected message %T", m)
+	}
+	return s.Send(req)
+}
+
+func (s *replayStream) RecvMsg(m any) error {
+	resp, err := s.Recv()
+	if err != nil {
+		return err
+	}
+	dst, ok := m.(*gpb.SubscribeResponse)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected message %T", m)
+	}
+	proto.Merge(dst, resp)
+	return nil
+}
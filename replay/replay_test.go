@@ -0,0 +1,369 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replay_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/ygnmi/exampleoc"
+	"github.com/openconfig/ygnmi/exampleoc/exampleocpath"
+	"github.com/openconfig/ygnmi/internal/testutil"
+	"github.com/openconfig/ygnmi/replay"
+	"github.com/openconfig/ygnmi/ygnmi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/local"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+const (
+	// syncDelay is the delay of the sync response of ONCE subscriptions.
+	syncDelay = 100 * time.Millisecond
+	// streamDelay is the delay between the updates of STREAM subscriptions.
+	streamDelay = 50 * time.Millisecond
+	// streamUpdates is the number of updates of STREAM subscriptions, after which the stream ends.
+	streamUpdates = 3
+)
+
+// server serves the value "foo" for every path.
+// ONCE subscriptions have a delayed sync response, STREAM subscriptions have delayed updates,
+// and POLL subscriptions respond to each poll with an update and a sync response.
+type server struct {
+	gpb.UnimplementedGNMIServer
+}
+
+func (*server) notification(paths []*gpb.Path) *gpb.Notification {
+	n := &gpb.Notification{Timestamp: 1}
+	for _, p := range paths {
+		n.Update = append(n.Update, &gpb.Update{Path: p, Val: &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "foo"}}})
+	}
+	return n
+}
+
+func (s *server) Get(_ context.Context, req *gpb.GetRequest) (*gpb.GetResponse, error) {
+	return &gpb.GetResponse{Notification: []*gpb.Notification{s.notification(req.GetPath())}}, nil
+}
+
+func (*server) Set(context.Context, *gpb.SetRequest) (*gpb.SetResponse, error) {
+	return nil, status.Error(codes.PermissionDenied, "read-only")
+}
+
+func (s *server) Subscribe(srv gpb.GNMI_SubscribeServer) error {
+	req, err := srv.Recv()
+	if err != nil {
+		return err
+	}
+	var paths []*gpb.Path
+	for _, sub := range req.GetSubscribe().GetSubscription() {
+		paths = append(paths, sub.GetPath())
+	}
+	update := func(ts int64) error {
+		n := s.notification(paths)
+		n.Timestamp = ts
+		return srv.Send(&gpb.SubscribeResponse{Response: &gpb.SubscribeResponse_Update{Update: n}})
+	}
+	sync := func() error {
+		return srv.Send(&gpb.SubscribeResponse{Response: &gpb.SubscribeResponse_SyncResponse{SyncResponse: true}})
+	}
+	switch req.GetSubscribe().GetMode() {
+	case gpb.SubscriptionList_STREAM:
+		for ts := int64(1); ts <= streamUpdates; ts++ {
+			if err := update(ts); err != nil {
+				return err
+			}
+			time.Sleep(streamDelay)
+		}
+		return nil
+	case gpb.SubscriptionList_POLL:
+		for ts := int64(1); ; ts++ {
+			if err := update(ts); err != nil {
+				return err
+			}
+			if err := sync(); err != nil {
+				return err
+			}
+			req, err := srv.Recv()
+			if err != nil {
+				return nil
+			}
+			if req.GetPoll() == nil {
+				return status.Errorf(codes.InvalidArgument, "unexpected request %v", req)
+			}
+		}
+	default:
+		if err := update(1); err != nil {
+			return err
+		}
+		time.Sleep(syncDelay)
+		return sync()
+	}
+}
+
+func newServerConn(t *testing.T) gpb.GNMIClient {
+	t.Helper()
+	s := grpc.NewServer(grpc.Creds(local.NewCredentials()))
+	gpb.RegisterGNMIServer(s, &server{})
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		//nolint:errcheck // Don't care about this error.
+		s.Serve(l)
+	}()
+	t.Cleanup(s.Stop)
+	conn, err := grpc.NewClient(l.Addr().String(), grpc.WithTransportCredentials(local.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return gpb.NewGNMIClient(conn)
+}
+
+// session makes the RPCs of the test with c, and returns the results.
+func session(t *testing.T, c *ygnmi.Client) (lookup, get string, setErr error) {
+	t.Helper()
+	ctx := context.Background()
+	one := exampleocpath.Root().Parent().Child().One().State()
+	v, err := ygnmi.Lookup(ctx, c, one)
+	if err != nil {
+		t.Fatalf("Lookup() returned unexpected error: %v", err)
+	}
+	lookup, _ = v.Val()
+	if get, err = ygnmi.Get(ctx, c, one, ygnmi.WithUseGet()); err != nil {
+		t.Fatalf("Get() returned unexpected error: %v", err)
+	}
+	_, setErr = ygnmi.Replace(ctx, c, exampleocpath.Root().Parent().Child().Three().Config(), exampleoc.Child_Three_ONE)
+	return lookup, get, setErr
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	var recording bytes.Buffer
+	rec := replay.NewRecorder(newServerConn(t), &recording)
+	c, err := ygnmi.NewClient(rec)
+	if err != nil {
+		t.Fatalf("NewClient() returned unexpected error: %v", err)
+	}
+	wantLookup, wantGet, wantSetErr := session(t, c)
+	if err := rec.Err(); err != nil {
+		t.Fatalf("Recorder.Err() returned unexpected error: %v", err)
+	}
+	// The Subscribe request, update and sync, and the request and response of Get and Set.
+	// The Subscribe stream is cancelled after the sync, which isn't recorded.
+	if got := strings.Count(recording.String(), "\n"); got != 7 {
+		t.Errorf("Recorded %d events, want 7:\n%s", got, recording.String())
+	}
+
+	rc, err := replay.NewClient(bytes.NewReader(recording.Bytes()))
+	if err != nil {
+		t.Fatalf("replay.NewClient() returned unexpected error: %v", err)
+	}
+	c, err = ygnmi.NewClient(rc)
+	if err != nil {
+		t.Fatalf("NewClient() returned unexpected error: %v", err)
+	}
+	start := time.Now()
+	gotLookup, gotGet, gotSetErr := session(t, c)
+	if d := time.Since(start); d < syncDelay {
+		t.Errorf("Replayed session took %v, want at least the recorded delay %v", d, syncDelay)
+	}
+	if gotLookup != wantLookup || gotGet != wantGet {
+		t.Errorf("Replayed session got Lookup %q and Get %q, want %q and %q", gotLookup, gotGet, wantLookup, wantGet)
+	}
+	if status.Code(gotSetErr) != codes.PermissionDenied || status.Code(wantSetErr) != codes.PermissionDenied {
+		t.Errorf("Replace() got errors %v when recorded and %v when replayed, want PermissionDenied", wantSetErr, gotSetErr)
+	}
+
+	// All the recorded RPCs were replayed, so there is no match left.
+	if _, err := ygnmi.Lookup(context.Background(), c, exampleocpath.Root().Parent().Child().One().State()); status.Code(err) != codes.NotFound {
+		t.Errorf("Lookup() got error %v, want NotFound", err)
+	}
+}
+
+// subscribeRequest returns a Subscribe request in the mode for the leaf "one".
+func subscribeRequest(t *testing.T, mode gpb.SubscriptionList_Mode) *gpb.SubscribeRequest {
+	t.Helper()
+	return &gpb.SubscribeRequest{Request: &gpb.SubscribeRequest_Subscribe{Subscribe: &gpb.SubscriptionList{
+		Subscription: []*gpb.Subscription{{Path: testutil.GNMIPath(t, "/parent/child/state/one")}},
+		Mode:         mode,
+	}}}
+}
+
+// pollRequest triggers a poll of a POLL subscription.
+var pollRequest = &gpb.SubscribeRequest{Request: &gpb.SubscribeRequest_Poll{Poll: &gpb.Poll{}}}
+
+// streamSession sends the request on a Subscribe stream of c, and returns the responses received until the end of the stream.
+func streamSession(c gpb.GNMIClient, req *gpb.SubscribeRequest) ([]*gpb.SubscribeResponse, error) {
+	sub, err := c.Subscribe(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	if err := sub.Send(req); err != nil {
+		return nil, err
+	}
+	var resps []*gpb.SubscribeResponse
+	for {
+		resp, err := sub.Recv()
+		if errors.Is(err, io.EOF) {
+			return resps, nil
+		}
+		if err != nil {
+			return resps, err
+		}
+		resps = append(resps, resp)
+	}
+}
+
+// pollSession sends the requests on a Subscribe stream of c, and returns the responses
+// received until the sync response following each request.
+func pollSession(c gpb.GNMIClient, reqs ...*gpb.SubscribeRequest) ([]*gpb.SubscribeResponse, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sub, err := c.Subscribe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var resps []*gpb.SubscribeResponse
+	for _, req := range reqs {
+		if err := sub.Send(req); err != nil {
+			return resps, err
+		}
+		for {
+			resp, err := sub.Recv()
+			if err != nil {
+				return resps, err
+			}
+			resps = append(resps, resp)
+			if resp.GetSyncResponse() {
+				break
+			}
+		}
+	}
+	return resps, nil
+}
+
+func TestReplaySubscribe(t *testing.T) {
+	// record returns the recording of the session made with the Recorder and the responses received.
+	record := func(t *testing.T, session func(gpb.GNMIClient) ([]*gpb.SubscribeResponse, error)) (*bytes.Buffer, []*gpb.SubscribeResponse) {
+		t.Helper()
+		var recording bytes.Buffer
+		rec := replay.NewRecorder(newServerConn(t), &recording)
+		resps, err := session(rec)
+		if err != nil {
+			t.Fatalf("Recorded session returned unexpected error: %v", err)
+		}
+		if err := rec.Err(); err != nil {
+			t.Fatalf("Recorder.Err() returned unexpected error: %v", err)
+		}
+		return &recording, resps
+	}
+	newClient := func(t *testing.T, recording *bytes.Buffer) *replay.Client {
+		t.Helper()
+		rc, err := replay.NewClient(bytes.NewReader(recording.Bytes()))
+		if err != nil {
+			t.Fatalf("replay.NewClient() returned unexpected error: %v", err)
+		}
+		return rc
+	}
+
+	t.Run("stream", func(t *testing.T) {
+		stream := func(c gpb.GNMIClient) ([]*gpb.SubscribeResponse, error) {
+			return streamSession(c, subscribeRequest(t, gpb.SubscriptionList_STREAM))
+		}
+		recording, want := record(t, stream)
+		if len(want) != streamUpdates {
+			t.Fatalf("Recorded %d responses, want %d", len(want), streamUpdates)
+		}
+		start := time.Now()
+		got, err := stream(newClient(t, recording))
+		if err != nil {
+			t.Fatalf("Replayed session returned unexpected error: %v", err)
+		}
+		if d, delays := time.Since(start), streamUpdates*streamDelay; d < delays {
+			t.Errorf("Replayed session took %v, want at least the recorded delays %v", d, delays)
+		}
+		if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+			t.Errorf("Replayed session got unexpected responses (-want,+got):\n%s", diff)
+		}
+	})
+
+	poll := func(c gpb.GNMIClient) ([]*gpb.SubscribeResponse, error) {
+		return pollSession(c, subscribeRequest(t, gpb.SubscriptionList_POLL), pollRequest, pollRequest)
+	}
+	recording, want := record(t, poll)
+	t.Run("poll", func(t *testing.T) {
+		// An update and a sync response for the subscription and each poll.
+		if len(want) != 6 {
+			t.Fatalf("Recorded %d responses, want 6", len(want))
+		}
+		got, err := poll(newClient(t, recording))
+		if err != nil {
+			t.Fatalf("Replayed session returned unexpected error: %v", err)
+		}
+		if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+			t.Errorf("Replayed session got unexpected responses (-want,+got):\n%s", diff)
+		}
+	})
+
+	t.Run("poll mismatch", func(t *testing.T) {
+		got, err := pollSession(newClient(t, recording), subscribeRequest(t, gpb.SubscriptionList_POLL), pollRequest, subscribeRequest(t, gpb.SubscriptionList_POLL))
+		if status.Code(err) != codes.FailedPrecondition {
+			t.Fatalf("Replayed session got error %v, want FailedPrecondition", err)
+		}
+		// The responses to the subscription and the first poll are replayed.
+		if diff := cmp.Diff(want[:4], got, protocmp.Transform()); diff != "" {
+			t.Errorf("Replayed session got unexpected responses (-want,+got):\n%s", diff)
+		}
+	})
+}
+
+func TestNewClientErrors(t *testing.T) {
+	tests := []struct {
+		desc      string
+		recording string
+		wantErr   string
+	}{{
+		desc:      "invalid json",
+		recording: "{",
+		wantErr:   "failed to decode event 1",
+	}, {
+		desc:      "unknown rpc",
+		recording: `{"call":1,"rpc":"Foo","kind":"request","message":{}}`,
+		wantErr:   `unexpected request event of RPC "Foo"`,
+	}, {
+		desc:      "call without request",
+		recording: `{"call":1,"rpc":"Get","kind":"response","message":{}}`,
+		wantErr:   "call 1 doesn't start with a request",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			_, err := replay.NewClient(strings.NewReader(tt.recording))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("NewClient() got error %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
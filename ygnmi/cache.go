@@ -0,0 +1,225 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/openconfig/gnmi/ctree"
+	"github.com/openconfig/gnmi/path"
+	"github.com/openconfig/ygot/util"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// Cache maintains the latest values of a subtree using a single STREAM subscription.
+// Lookup, LookupAll, Get and GetAll can be answered from the cache using WithCache,
+// instead of creating a new ONCE subscription for each call.
+// The Timestamp and RecvTimestamp of the cached values are preserved,
+// so that callers can check how fresh the returned values are.
+type Cache struct {
+	root *gpb.Path
+	// state is true if the cache subscribes to state, false if it subscribes to config.
+	state  bool
+	tree   *ctree.Tree
+	cancel context.CancelFunc
+
+	// synced is closed when the subscription has received its initial sync.
+	synced chan struct{}
+	// done is closed when the subscription fails with err.
+	done chan struct{}
+	err  error
+}
+
+// NewCache starts a STREAM subscription to the query and caches the values received on it.
+// Queries answered by the cache must be within the subtree of the query, and be state queries
+// if the query is a state query, or config queries otherwise.
+// The options are used for the subscription, WithUseGet and WithFT are not supported.
+// Without WithResubscribe, the cache fails permanently when the subscription fails or is closed by the target.
+// With WithResubscribe, the subscription is reopened and the cache is re-synced with its initial sync,
+// lookups are answered with the values received before the failure in the meantime.
+// The subscription is stopped when the context is cancelled or Close is called.
+func NewCache(ctx context.Context, c *Client, q UntypedQuery, opts ...Option) (*Cache, error) {
+	resolvedOpts := resolveClientOpts(c, opts)
+	if resolvedOpts.useGet {
+		return nil, fmt.Errorf("using gnmi.Get is not supported for caches")
+	}
+	if resolvedOpts.ft != nil {
+		return nil, fmt.Errorf("functional translators are not supported for caches")
+	}
+	root, err := resolvePath(q.PathStruct())
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve path: %w", err)
+	}
+	var paths []*gpb.Path
+	var params []*SubscriptionParams
+	for _, p := range q.subPaths() {
+		sp, err := resolvePath(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve path: %w", err)
+		}
+		paths = append(paths, sp)
+		params = append(params, pathParams(p))
+	}
+	ctx, cancel := context.WithCancel(NewContext(ctx, q))
+	dt := gpb.GetRequest_CONFIG
+	if q.IsState() {
+		dt = gpb.GetRequest_STATE
+	}
+	subFn := func() (gpb.GNMI_SubscribeClient, error) {
+		return subscribePaths(ctx, c, q, paths, params, gpb.SubscriptionList_STREAM, dt, resolvedOpts)
+	}
+	sub, err := subFn()
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to subscribe to path: %w", err)
+	}
+	cache := &Cache{
+		root:   root,
+		state:  q.IsState(),
+		tree:   &ctree.Tree{},
+		cancel: cancel,
+		synced: make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go cache.run(ctx, sub, subFn, resolvedOpts)
+	return cache, nil
+}
+
+// Close stops the subscription of the cache.
+// Lookups using the cache fail after it is closed.
+func (c *Cache) Close() {
+	c.cancel()
+}
+
+// Err returns the error the subscription of the cache failed with,
+// or nil if it is still running.
+func (c *Cache) Err() error {
+	select {
+	case <-c.done:
+		return c.err
+	default:
+		return nil
+	}
+}
+
+// run receives responses until the subscription fails, updating the tree.
+// The subscription is reopened with subFn if a resubscribe policy is set.
+func (c *Cache) run(ctx context.Context, sub gpb.GNMI_SubscribeClient, subFn func() (gpb.GNMI_SubscribeClient, error), o *opt) {
+	var syncOnce sync.Once
+	rs := newResubscriber(o.resubscribe, o.log)
+	// resyncing is true while the initial sync of a reopened subscription is buffered in resyncData.
+	var resyncing bool
+	var resyncData []*DataPoint
+	for {
+		data, synced, err := receive(ctx, sub, nil, true, c.root, o)
+		if err != nil && rs.shouldRetry(ctx, err) {
+			sub, err = rs.resubscribe(ctx, subFn, err)
+			if err == nil {
+				resyncing, resyncData = true, nil
+				continue
+			}
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = fmt.Errorf("subscription closed: %w", err)
+			}
+			c.err = err
+			close(c.done)
+			return
+		}
+		// resynced is true when the initial sync of a reopened subscription is complete.
+		var resynced bool
+		switch {
+		case resyncing && !synced:
+			resyncData = append(resyncData, data...)
+			continue
+		case resyncing:
+			// Values that disappeared while the subscription was down are deleted along with the resync.
+			data = rs.resync(resyncData)
+			rs.reset()
+			resyncing, resyncData, resynced = false, nil, true
+		case synced:
+			syncOnce.Do(func() { close(c.synced) })
+			continue
+		}
+		for _, dp := range data {
+			idx := path.ToStrings(dp.Path, false)
+			if dp.Value == nil {
+				c.tree.Delete(idx)
+				continue
+			}
+			if err := c.tree.Add(idx, dp); err != nil {
+				o.log.warning(ctx, "Failed to cache datapoint", "datapoint", dp.String(), "error", err)
+			}
+		}
+		rs.record(data)
+		if resynced {
+			// The first subscription may have failed before its initial sync.
+			syncOnce.Do(func() { close(c.synced) })
+		}
+	}
+}
+
+// datapoints returns the cached datapoints matching the query, waiting for the initial sync if needed.
+func (c *Cache) datapoints(ctx context.Context, q UntypedQuery) ([]*DataPoint, error) {
+	select {
+	case <-c.synced:
+	case <-c.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if err := c.Err(); err != nil {
+		return nil, fmt.Errorf("cache subscription failed: %w", err)
+	}
+	if q.IsState() != c.state {
+		return nil, fmt.Errorf("query %s is a %s query, but the cache is for %s", q, queryKind(q.IsState()), queryKind(c.state))
+	}
+	var data []*DataPoint
+	for _, p := range q.subPaths() {
+		queryPath, err := resolvePath(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve path: %w", err)
+		}
+		if !util.PathMatchesQuery(queryPath, c.root) {
+			return nil, fmt.Errorf("query path %s is not within the cached subtree %s", pathToString(queryPath), pathToString(c.root))
+		}
+		err = c.tree.Query(path.ToStrings(queryPath, false), func(_ []string, _ *ctree.Leaf, val any) error {
+			dp := val.(*DataPoint)
+			if util.PathMatchesQuery(dp.Path, queryPath) {
+				// Copy the datapoint, so that the cached one isn't modified by the caller.
+				cp := *dp
+				data = append(data, &cp)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// queryKind returns the kind of data of a query, state or config.
+func queryKind(state bool) string {
+	if state {
+		return "state"
+	}
+	return "config"
+}
@@ -0,0 +1,195 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/ygnmi/exampleoc/exampleocpath"
+	"github.com/openconfig/ygnmi/internal/testutil"
+	"github.com/openconfig/ygnmi/ygnmi"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func TestCache(t *testing.T) {
+	fooPath := testutil.GNMIPath(t, "/model/a/single-key[key=foo]/state/value")
+	barPath := testutil.GNMIPath(t, "/model/a/single-key[key=bar]/state/value")
+	intResp := func(ts int64, p *gpb.Path, val int64) *gpb.SubscribeResponse {
+		return &gpb.SubscribeResponse{Response: &gpb.SubscribeResponse_Update{Update: &gpb.Notification{
+			Timestamp: ts,
+			Update: []*gpb.Update{{
+				Path: p,
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_IntVal{IntVal: val}},
+			}},
+		}}}
+	}
+	srv := &pushServer{
+		initial: []*gpb.SubscribeResponse{
+			intResp(100, fooPath, 10),
+			intResp(100, barPath, 11),
+			{Response: &gpb.SubscribeResponse_SyncResponse{SyncResponse: true}},
+		},
+		updates: make(chan *gpb.SubscribeResponse),
+	}
+	c := newServerClient(t, srv)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cache, err := ygnmi.NewCache(ctx, c, exampleocpath.Root().Model().State())
+	if err != nil {
+		t.Fatalf("NewCache() returned unexpected error: %v", err)
+	}
+	fooQuery := exampleocpath.Root().Model().SingleKey("foo").Value().State()
+	barQuery := exampleocpath.Root().Model().SingleKey("bar").Value().State()
+
+	t.Run("lookup", func(t *testing.T) {
+		val, err := ygnmi.Lookup(ctx, c, fooQuery, ygnmi.WithCache(cache))
+		if err != nil {
+			t.Fatalf("Lookup() returned unexpected error: %v", err)
+		}
+		if got, ok := val.Val(); !ok || got != 10 {
+			t.Errorf("Lookup() got value %v, want 10", val)
+		}
+		if want := time.Unix(0, 100); !val.Timestamp.Equal(want) {
+			t.Errorf("Lookup() got timestamp %v, want %v", val.Timestamp, want)
+		}
+		if val.RecvTimestamp.IsZero() {
+			t.Errorf("Lookup() got zero recv timestamp")
+		}
+	})
+	t.Run("get all", func(t *testing.T) {
+		got, err := ygnmi.GetAll(ctx, c, exampleocpath.Root().Model().SingleKeyAny().Value().State(), ygnmi.WithCache(cache))
+		if err != nil {
+			t.Fatalf("GetAll() returned unexpected error: %v", err)
+		}
+		if diff := cmp.Diff([]int64{11, 10}, got); diff != "" {
+			t.Errorf("GetAll() returned unexpected diff (-want,+got):\n%s", diff)
+		}
+	})
+	t.Run("updates and deletes", func(t *testing.T) {
+		srv.updates <- &gpb.SubscribeResponse{Response: &gpb.SubscribeResponse_Update{Update: &gpb.Notification{
+			Timestamp: 101,
+			Delete:    []*gpb.Path{testutil.GNMIPath(t, "/model/a/single-key[key=foo]")},
+			Update: []*gpb.Update{{
+				Path: barPath,
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_IntVal{IntVal: 12}},
+			}},
+		}}}
+		for {
+			got, err := ygnmi.Get(ctx, c, barQuery, ygnmi.WithCache(cache))
+			if err != nil {
+				t.Fatalf("Get() returned unexpected error: %v", err)
+			}
+			if got == 12 {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+		if _, err := ygnmi.Get(ctx, c, fooQuery, ygnmi.WithCache(cache)); !errors.Is(err, ygnmi.ErrNotPresent) {
+			t.Errorf("Get() returned error %v, want ErrNotPresent", err)
+		}
+	})
+	t.Run("query outside of subtree", func(t *testing.T) {
+		_, err := ygnmi.Lookup(ctx, c, exampleocpath.Root().RemoteContainer().ALeaf().State(), ygnmi.WithCache(cache))
+		if d := errdiff.Substring(err, "not within the cached subtree"); d != "" {
+			t.Errorf("Lookup() returned unexpected error: %s", d)
+		}
+	})
+	t.Run("config query", func(t *testing.T) {
+		_, err := ygnmi.Lookup(ctx, c, exampleocpath.Root().Model().SingleKey("foo").Value().Config(), ygnmi.WithCache(cache))
+		if d := errdiff.Substring(err, "is a config query, but the cache is for state"); d != "" {
+			t.Errorf("Lookup() returned unexpected error: %s", d)
+		}
+	})
+	if got := srv.Requests(); got != 1 {
+		t.Errorf("server got %d Subscribe RPCs, want 1", got)
+	}
+
+	cache.Close()
+	for cache.Err() == nil {
+		time.Sleep(time.Millisecond)
+	}
+	_, err = ygnmi.Lookup(ctx, c, fooQuery, ygnmi.WithCache(cache))
+	if d := errdiff.Substring(err, "cache subscription failed"); d != "" {
+		t.Errorf("Lookup() after Close returned unexpected error: %s", d)
+	}
+}
+
+func TestCacheResubscribe(t *testing.T) {
+	fooPath := testutil.GNMIPath(t, "/model/a/single-key[key=foo]/state/value")
+	initial := []*gpb.SubscribeResponse{{
+		Response: &gpb.SubscribeResponse_Update{Update: &gpb.Notification{
+			Timestamp: 100,
+			Update: []*gpb.Update{{
+				Path: fooPath,
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_IntVal{IntVal: 10}},
+			}},
+		}},
+	}, {
+		Response: &gpb.SubscribeResponse_SyncResponse{SyncResponse: true},
+	}}
+	fooQuery := exampleocpath.Root().Model().SingleKey("foo").Value().State()
+
+	tests := []struct {
+		desc string
+		opts []ygnmi.Option
+		// failBeforeSync makes the first subscription fail before its initial sync instead of after it.
+		failBeforeSync bool
+		wantErr        string
+	}{{
+		desc:    "without resubscribe",
+		wantErr: "cache subscription failed",
+	}, {
+		desc: "with resubscribe",
+		opts: []ygnmi.Option{ygnmi.WithResubscribe(&ygnmi.ResubscribePolicy{InitialBackoff: time.Millisecond})},
+	}, {
+		desc:           "with resubscribe before sync",
+		opts:           []ygnmi.Option{ygnmi.WithResubscribe(&ygnmi.ResubscribePolicy{InitialBackoff: time.Millisecond})},
+		failBeforeSync: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			srv := &pushServer{initial: initial, updates: make(chan *gpb.SubscribeResponse), failFirst: true, failBeforeSync: tt.failBeforeSync}
+			c := newServerClient(t, srv)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			cache, err := ygnmi.NewCache(ctx, c, exampleocpath.Root().Model().State(), tt.opts...)
+			if err != nil {
+				t.Fatalf("NewCache() returned unexpected error: %v", err)
+			}
+			defer cache.Close()
+			// Wait for the failure, or for the second subscription.
+			for cache.Err() == nil && srv.Requests() < 2 {
+				time.Sleep(time.Millisecond)
+			}
+			got, err := ygnmi.Get(ctx, c, fooQuery, ygnmi.WithCache(cache))
+			if d := errdiff.Substring(err, tt.wantErr); d != "" {
+				t.Fatalf("Get() returned unexpected error: %s", d)
+			}
+			if err == nil && got != 10 {
+				t.Errorf("Get() got %v, want 10", got)
+			}
+			if tt.wantErr == "" && cache.Err() != nil {
+				t.Errorf("Err() got %v, want nil", cache.Err())
+			}
+		})
+	}
+}
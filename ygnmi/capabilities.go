@@ -0,0 +1,143 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// Capabilities contains the capabilities advertised by a target.
+type Capabilities struct {
+	// Version is the gNMI version supported by the target.
+	Version string
+	// Encodings are the encodings supported by the target.
+	Encodings []gpb.Encoding
+	// Models are the models supported by the target.
+	Models []*gpb.ModelData
+}
+
+// Capabilities fetches the capabilities of the target with a gNMI Capabilities request.
+func (c *Client) Capabilities(ctx context.Context) (*Capabilities, error) {
+	req := &gpb.CapabilityRequest{}
+	c.log.request(ctx, "Capabilities", req)
+	resp, err := c.gnmiC.Capabilities(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("gNMI failed to get Capabilities: %w", err)
+	}
+	c.log.request(ctx, "Capabilities", resp)
+	return &Capabilities{
+		Version:   resp.GetGNMIVersion(),
+		Encodings: resp.GetSupportedEncodings(),
+		Models:    resp.GetSupportedModels(),
+	}, nil
+}
+
+// SupportsEncoding returns whether the target supports the encoding.
+func (c *Capabilities) SupportsEncoding(enc gpb.Encoding) bool {
+	return slices.Contains(c.Encodings, enc)
+}
+
+// Model returns the model with the name supported by the target, or nil if it is not supported.
+func (c *Capabilities) Model(name string) *gpb.ModelData {
+	for _, m := range c.Models {
+		if m.GetName() == name {
+			return m
+		}
+	}
+	return nil
+}
+
+// ModelMismatch is a model the generated code was built from that
+// the target doesn't support with the same version.
+type ModelMismatch struct {
+	// Name is the name of the model.
+	Name string
+	// Want is the version of the model the generated code was built from.
+	Want string
+	// Got is the version of the model supported by the target.
+	Got string
+	// Missing is true if the target doesn't support the model.
+	Missing bool
+}
+
+func (m *ModelMismatch) String() string {
+	if m.Missing && m.Want == "" {
+		return fmt.Sprintf("device does not support %s", m.Name)
+	}
+	if m.Missing {
+		return fmt.Sprintf("device does not support %s, bindings were generated for %s", m.Name, m.Want)
+	}
+	return fmt.Sprintf("device has %s %s, bindings were generated for %s", m.Name, m.Got, m.Want)
+}
+
+// ModelReport is the result of comparing the models supported by a target
+// with the models the generated code was built from.
+type ModelReport struct {
+	// Mismatches are the models which are missing or have different versions on the target.
+	Mismatches []*ModelMismatch
+}
+
+func (r *ModelReport) String() string {
+	if len(r.Mismatches) == 0 {
+		return "all models are supported by the device"
+	}
+	var b strings.Builder
+	for i, m := range r.Mismatches {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(m.String())
+	}
+	return b.String()
+}
+
+// Err returns an error containing the report if there are any mismatches, otherwise nil.
+func (r *ModelReport) Err() error {
+	if len(r.Mismatches) == 0 {
+		return nil
+	}
+	return fmt.Errorf("device models are incompatible with the generated code:\n%v", r)
+}
+
+// CheckModels compares the models supported by the target with the models the generated code was built from.
+// The want models are usually the ΓModelData variable of a package generated with --include_model_data.
+// A model is reported if the target doesn't support it or the versions differ.
+// Models without a version, such as the extension modules, are only checked for presence.
+func (c *Capabilities) CheckModels(want []*gpb.ModelData) *ModelReport {
+	report := &ModelReport{}
+	for _, w := range want {
+		got := c.Model(w.GetName())
+		switch {
+		case got == nil:
+			report.Mismatches = append(report.Mismatches, &ModelMismatch{
+				Name:    w.GetName(),
+				Want:    w.GetVersion(),
+				Missing: true,
+			})
+		case w.GetVersion() != "" && got.GetVersion() != w.GetVersion():
+			report.Mismatches = append(report.Mismatches, &ModelMismatch{
+				Name: w.GetName(),
+				Want: w.GetVersion(),
+				Got:  got.GetVersion(),
+			})
+		}
+	}
+	return report
+}
@@ -0,0 +1,154 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/ygnmi/ygnmi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// capabilitiesServer is a gNMI server that responds to Capabilities RPCs with a fixed response.
+type capabilitiesServer struct {
+	gpb.UnimplementedGNMIServer
+	resp *gpb.CapabilityResponse
+	err  error
+}
+
+func (s *capabilitiesServer) Capabilities(context.Context, *gpb.CapabilityRequest) (*gpb.CapabilityResponse, error) {
+	return s.resp, s.err
+}
+
+func TestCapabilities(t *testing.T) {
+	tests := []struct {
+		desc    string
+		srv     *capabilitiesServer
+		want    *ygnmi.Capabilities
+		wantErr string
+	}{{
+		desc: "success",
+		srv: &capabilitiesServer{
+			resp: &gpb.CapabilityResponse{
+				GNMIVersion:        "0.10.0",
+				SupportedEncodings: []gpb.Encoding{gpb.Encoding_JSON_IETF, gpb.Encoding_PROTO},
+				SupportedModels: []*gpb.ModelData{{
+					Name:         "openconfig-interfaces",
+					Organization: "OpenConfig working group",
+					Version:      "2.4.3",
+				}},
+			},
+		},
+		want: &ygnmi.Capabilities{
+			Version:   "0.10.0",
+			Encodings: []gpb.Encoding{gpb.Encoding_JSON_IETF, gpb.Encoding_PROTO},
+			Models: []*gpb.ModelData{{
+				Name:         "openconfig-interfaces",
+				Organization: "OpenConfig working group",
+				Version:      "2.4.3",
+			}},
+		},
+	}, {
+		desc:    "error",
+		srv:     &capabilitiesServer{err: status.Error(codes.Unimplemented, "no capabilities")},
+		wantErr: "no capabilities",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			c := newServerClient(t, tt.srv)
+			got, err := c.Capabilities(context.Background())
+			if diff := errdiff.Substring(err, tt.wantErr); diff != "" {
+				t.Fatalf("Capabilities() returned unexpected diff: %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("Capabilities() returned unexpected diff (-want,+got):\n%s", diff)
+			}
+			if !got.SupportsEncoding(gpb.Encoding_PROTO) {
+				t.Errorf("SupportsEncoding(PROTO) got false, want true")
+			}
+			if got.SupportsEncoding(gpb.Encoding_JSON) {
+				t.Errorf("SupportsEncoding(JSON) got true, want false")
+			}
+		})
+	}
+}
+
+func TestCheckModels(t *testing.T) {
+	caps := &ygnmi.Capabilities{
+		Models: []*gpb.ModelData{{
+			Name:    "openconfig-interfaces",
+			Version: "2.4.3",
+		}, {
+			Name:    "openconfig-system",
+			Version: "1.0.0",
+		}, {
+			Name: "openconfig-extensions",
+		}},
+	}
+	tests := []struct {
+		desc       string
+		want       []*gpb.ModelData
+		wantReport string
+		wantErr    string
+	}{{
+		desc: "compatible",
+		want: []*gpb.ModelData{{
+			Name:    "openconfig-system",
+			Version: "1.0.0",
+		}, {
+			Name: "openconfig-extensions",
+		}},
+		wantReport: "all models are supported by the device",
+	}, {
+		desc: "mismatches",
+		want: []*gpb.ModelData{{
+			Name:    "openconfig-interfaces",
+			Version: "3.0.0",
+		}, {
+			Name:    "openconfig-system",
+			Version: "1.0.0",
+		}, {
+			Name:    "openconfig-bgp",
+			Version: "9.0.0",
+		}, {
+			Name: "openconfig-types",
+		}},
+		wantReport: "device has openconfig-interfaces 2.4.3, bindings were generated for 3.0.0\n" +
+			"device does not support openconfig-bgp, bindings were generated for 9.0.0\n" +
+			"device does not support openconfig-types",
+		wantErr: "device models are incompatible",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			report := caps.CheckModels(tt.want)
+			if got := report.String(); got != tt.wantReport {
+				t.Errorf("CheckModels() got report:\n%s\nwant:\n%s", got, tt.wantReport)
+			}
+			if diff := errdiff.Substring(report.Err(), tt.wantErr); diff != "" {
+				t.Errorf("CheckModels().Err() returned unexpected diff: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	gnmiextpb "github.com/openconfig/gnmi/proto/gnmi_ext"
+)
+
+// PendingCommit is a commit created with SetBatch.SetWithCommit,
+// which the target rolls back unless it is confirmed before the rollback duration expires.
+type PendingCommit struct {
+	// ID is the ID of the commit sent to the target.
+	ID string
+	// Result is the result of the SetRequest which created the commit.
+	Result *Result
+
+	client *Client
+	mu     sync.Mutex
+	// done is the action that ended the commit, or empty if the commit is ongoing.
+	done string
+}
+
+// SetWithCommit sends the batch as a confirmed commit, using the gNMI commit extension.
+// The target applies the changes, and rolls them back if the commit isn't confirmed
+// with PendingCommit.Confirm within rollbackDuration.
+// The changes can be rolled back immediately with PendingCommit.Cancel.
+// The target must support the commit extension, and only allows one ongoing commit at a time.
+func (sb *SetBatch) SetWithCommit(ctx context.Context, c *Client, rollbackDuration time.Duration, opts ...Option) (*PendingCommit, error) {
+	req, err := sb.request(ctx, c, opts)
+	if err != nil {
+		return nil, err
+	}
+	id, err := newCommitID()
+	if err != nil {
+		return nil, err
+	}
+	req.Extension = commitExtension(&gnmiextpb.Commit{
+		Id: id,
+		Action: &gnmiextpb.Commit_Commit{
+			Commit: &gnmiextpb.CommitRequest{RollbackDuration: durationpb.New(rollbackDuration)},
+		},
+	})
+	if reqs, err := resolveOpts(opts).split.split(req); err != nil {
+		return nil, err
+	} else if len(reqs) > 1 {
+		return nil, fmt.Errorf("commit can't span %d SetRequests", len(reqs))
+	}
+	res, err := c.sendSet(ctx, req, sb.operations(), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create commit %s: %w", id, err)
+	}
+	return &PendingCommit{
+		ID:     id,
+		Result: res,
+		client: c,
+	}, nil
+}
+
+// Confirm confirms the commit, so that the target keeps the changes.
+func (p *PendingCommit) Confirm(ctx context.Context) (*Result, error) {
+	return p.end(ctx, "confirm", "confirmed", &gnmiextpb.Commit{
+		Id:     p.ID,
+		Action: &gnmiextpb.Commit_Confirm{Confirm: &gnmiextpb.CommitConfirm{}},
+	})
+}
+
+// Cancel cancels the commit, so that the target rolls back the changes immediately.
+func (p *PendingCommit) Cancel(ctx context.Context) (*Result, error) {
+	return p.end(ctx, "cancel", "cancelled", &gnmiextpb.Commit{
+		Id:     p.ID,
+		Action: &gnmiextpb.Commit_Cancel{Cancel: &gnmiextpb.CommitCancel{}},
+	})
+}
+
+// end sends a SetRequest with the commit extension, which ends the commit.
+// If the request fails, the commit is still ongoing and the action may be retried.
+func (p *PendingCommit) end(ctx context.Context, action, done string, commit *gnmiextpb.Commit) (*Result, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.done != "" {
+		return nil, fmt.Errorf("commit %s already %s", p.ID, p.done)
+	}
+	req := &gpb.SetRequest{
+		Prefix: &gpb.Path{
+			Target: p.client.target,
+		},
+		Extension: commitExtension(commit),
+	}
+	res, err := p.client.sendSet(ctx, req, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to %s commit %s: %w", action, p.ID, err)
+	}
+	p.done = done
+	return res, nil
+}
+
+// commitExtension returns the extensions of a SetRequest containing the commit.
+func commitExtension(commit *gnmiextpb.Commit) []*gnmiextpb.Extension {
+	return []*gnmiextpb.Extension{{
+		Ext: &gnmiextpb.Extension_Commit{Commit: commit},
+	}}
+}
+
+// newCommitID returns a random ID for a commit.
+func newCommitID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate commit ID: %w", err)
+	}
+	return "ygnmi-" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,160 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/ygnmi/exampleoc/exampleocpath"
+	"github.com/openconfig/ygnmi/ygnmi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	gnmiextpb "github.com/openconfig/gnmi/proto/gnmi_ext"
+)
+
+// setServer is a gNMI server that records the SetRequests it receives,
+// and fails the requests while err is set.
+type setServer struct {
+	gpb.UnimplementedGNMIServer
+
+	mu       sync.Mutex
+	requests []*gpb.SetRequest
+	err      error
+}
+
+func (s *setServer) Set(_ context.Context, req *gpb.SetRequest) (*gpb.SetResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, req)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &gpb.SetResponse{Timestamp: 100}, nil
+}
+
+func (s *setServer) setErr(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+func (s *setServer) Requests() []*gpb.SetRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*gpb.SetRequest{}, s.requests...)
+}
+
+func TestSetWithCommit(t *testing.T) {
+	ctx := context.Background()
+	newBatch := func() *ygnmi.SetBatch {
+		b := &ygnmi.SetBatch{}
+		ygnmi.BatchReplace(b, exampleocpath.Root().Parent().Child().One().Config(), "foo")
+		return b
+	}
+	commitExt := func(commit *gnmiextpb.Commit) []*gnmiextpb.Extension {
+		return []*gnmiextpb.Extension{{Ext: &gnmiextpb.Extension_Commit{Commit: commit}}}
+	}
+
+	tests := []struct {
+		desc        string
+		end         func(*ygnmi.PendingCommit, context.Context) (*ygnmi.Result, error)
+		wantAction  *gnmiextpb.Commit
+		wantEndName string
+	}{{
+		desc:        "confirm",
+		end:         (*ygnmi.PendingCommit).Confirm,
+		wantAction:  &gnmiextpb.Commit{Action: &gnmiextpb.Commit_Confirm{Confirm: &gnmiextpb.CommitConfirm{}}},
+		wantEndName: "confirmed",
+	}, {
+		desc:        "cancel",
+		end:         (*ygnmi.PendingCommit).Cancel,
+		wantAction:  &gnmiextpb.Commit{Action: &gnmiextpb.Commit_Cancel{Cancel: &gnmiextpb.CommitCancel{}}},
+		wantEndName: "cancelled",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			srv := &setServer{}
+			c := newServerClient(t, srv)
+			pc, err := newBatch().SetWithCommit(ctx, c, time.Minute)
+			if err != nil {
+				t.Fatalf("SetWithCommit() returned unexpected error: %v", err)
+			}
+			if !strings.HasPrefix(pc.ID, "ygnmi-") {
+				t.Errorf("SetWithCommit() got commit ID %q, want prefix %q", pc.ID, "ygnmi-")
+			}
+			if got, want := pc.Result.Timestamp, time.Unix(0, 100); !got.Equal(want) {
+				t.Errorf("SetWithCommit() got result timestamp %v, want %v", got, want)
+			}
+
+			// A failed action leaves the commit ongoing.
+			srv.setErr(status.Error(codes.Unavailable, "unavailable"))
+			if _, err := tt.end(pc, ctx); err == nil {
+				t.Fatalf("%s() got no error while the server is failing", tt.desc)
+			}
+			srv.setErr(nil)
+			if _, err := tt.end(pc, ctx); err != nil {
+				t.Fatalf("%s() returned unexpected error: %v", tt.desc, err)
+			}
+			_, err = tt.end(pc, ctx)
+			if d := errdiff.Substring(err, "already "+tt.wantEndName); d != "" {
+				t.Errorf("%s() after the commit ended returned unexpected error: %s", tt.desc, d)
+			}
+
+			reqs := srv.Requests()
+			if len(reqs) != 3 {
+				t.Fatalf("Number of SetRequests got %d, want 3", len(reqs))
+			}
+			if len(reqs[0].GetReplace()) != 1 {
+				t.Errorf("Commit SetRequest got %d replaces, want 1", len(reqs[0].GetReplace()))
+			}
+			wantCommit := commitExt(&gnmiextpb.Commit{
+				Id: pc.ID,
+				Action: &gnmiextpb.Commit_Commit{
+					Commit: &gnmiextpb.CommitRequest{RollbackDuration: durationpb.New(time.Minute)},
+				},
+			})
+			if diff := cmp.Diff(wantCommit, reqs[0].GetExtension(), protocmp.Transform()); diff != "" {
+				t.Errorf("Commit SetRequest extensions returned unexpected diff (-want,+got):\n%s", diff)
+			}
+			tt.wantAction.Id = pc.ID
+			want := &gpb.SetRequest{
+				Prefix:    &gpb.Path{},
+				Extension: commitExt(tt.wantAction),
+			}
+			if diff := cmp.Diff(want, reqs[2], protocmp.Transform()); diff != "" {
+				t.Errorf("%s SetRequest returned unexpected diff (-want,+got):\n%s", tt.desc, diff)
+			}
+		})
+	}
+
+	t.Run("commit fails", func(t *testing.T) {
+		srv := &setServer{err: status.Error(codes.FailedPrecondition, "commit ongoing")}
+		c := newServerClient(t, srv)
+		_, err := newBatch().SetWithCommit(ctx, c, time.Minute)
+		if d := errdiff.Substring(err, "commit ongoing"); d != "" {
+			t.Errorf("SetWithCommit() returned unexpected error: %s", d)
+		}
+	})
+}
@@ -0,0 +1,197 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/openconfig/ygot/util"
+	"github.com/openconfig/ygot/ygot"
+	"google.golang.org/protobuf/proto"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// DriftEvent describes a leaf whose config on the device differs from the intended config, see DriftDetector.
+type DriftEvent struct {
+	// Path is the config path of the leaf.
+	Path *gpb.Path
+	// Intended is the intended value of the leaf, or nil if the leaf is configured on the device but not intended.
+	Intended *gpb.TypedValue
+	// Actual is the value of the leaf on the device, or nil if the leaf is intended but not configured.
+	Actual *gpb.TypedValue
+	// Resolved is true if the leaf doesn't drift anymore, in which case Actual is equal to Intended.
+	Resolved bool
+	// Timestamp is the latest timestamp of the config the drift was detected in.
+	Timestamp time.Time
+	// redactor masks the values in String if they are sensitive, see WithRedactionRules.
+	redactor *redactor
+}
+
+func (e *DriftEvent) String() string {
+	format := func(tv *gpb.TypedValue) string {
+		if tv == nil {
+			return "not present"
+		}
+		return formatTypedValue(e.redactor.typedValue(tv, e.Path))
+	}
+	if e.Resolved {
+		return fmt.Sprintf("%s: resolved", pathString(e.Path))
+	}
+	return fmt.Sprintf("%s: intended %s, actual %s", pathString(e.Path), format(e.Intended), format(e.Actual))
+}
+
+// DriftDetector subscribes to a non-leaf config query and compares the config on the device with an intended GoStruct.
+// Whenever a leaf diverges from the intended config, changes while diverged, or converges back, a DriftEvent is emitted.
+// This is intended for detecting out-of-band changes to the config, for example from the CLI.
+// The detector is built on a Reconciler, and leaves are compared with ygot.Diff.
+type DriftDetector[T ygot.GoStruct] struct {
+	rec      *Reconciler[T]
+	q        ConfigQuery[T]
+	intended ygot.GoStruct
+	redactor *redactor
+
+	mu sync.Mutex
+	// drift contains the unresolved events by path.
+	drift map[string]*DriftEvent
+}
+
+// NewDriftDetector creates a new drift detector for the config at the query, which should be equal to intended.
+// A nil intended GoStruct means that no config is intended at the query.
+func NewDriftDetector[T ygot.GoStruct](c *Client, q ConfigQuery[T], intended T, opts ...Option) (*DriftDetector[T], error) {
+	if q.isLeaf() {
+		return nil, fmt.Errorf("drift detection is only supported for non-leaf queries")
+	}
+	rec, err := NewReconciler(c, q, opts...)
+	if err != nil {
+		return nil, err
+	}
+	var intendedGS ygot.GoStruct = intended
+	if reflect.ValueOf(intended).IsNil() {
+		intendedGS = q.goStruct()
+	}
+	return &DriftDetector[T]{
+		rec:      rec,
+		q:        q,
+		intended: intendedGS,
+		redactor: c.log.redactor(),
+		drift:    map[string]*DriftEvent{},
+	}, nil
+}
+
+// Start starts the drift detector. fn is called with each DriftEvent, sorted by path,
+// starting with the drift of the initial config.
+// Like the callback of a Reconciler, fn can return ReconcilerAbortErr to stop the detector,
+// other errors are logged.
+func (d *DriftDetector[T]) Start(ctx context.Context, fn func(*DriftEvent) error) {
+	d.rec.Start(ctx, func(cfg *Value[T], _ *Value[T]) error {
+		events, err := d.update(cfg)
+		if err != nil {
+			return err
+		}
+		for _, e := range events {
+			if err := fn(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Await blocks until the drift detector exits.
+func (d *DriftDetector[T]) Await() error {
+	return d.rec.Await()
+}
+
+// Drift returns the leaves that currently drift from the intended config, sorted by path.
+func (d *DriftDetector[T]) Drift() []*DriftEvent {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	events := make([]*DriftEvent, 0, len(d.drift))
+	for _, e := range d.drift {
+		events = append(events, e)
+	}
+	sortDriftEvents(events)
+	return events
+}
+
+// update compares the config with the intended config, and returns the events
+// for the leaves whose drift changed since the last update.
+func (d *DriftDetector[T]) update(cfg *Value[T]) ([]*DriftEvent, error) {
+	queryPath, err := resolvePath(d.q.PathStruct())
+	if err != nil {
+		return nil, err
+	}
+	actual := ygot.GoStruct(d.q.goStruct())
+	if val, ok := cfg.Val(); ok {
+		actual = val
+	}
+	changes, err := diffGoStructs(actual, d.intended, d.q.isShadowPath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff actual and intended config: %w", err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var events []*DriftEvent
+	current := map[string]bool{}
+	for _, change := range changes {
+		path, err := util.JoinPaths(queryPath, change.Path)
+		if err != nil {
+			return nil, err
+		}
+		key := pathString(path)
+		current[key] = true
+		if prev, ok := d.drift[key]; ok && proto.Equal(prev.Actual, change.Old) && proto.Equal(prev.Intended, change.New) {
+			continue
+		}
+		e := &DriftEvent{
+			Path:      path,
+			Intended:  change.New,
+			Actual:    change.Old,
+			Timestamp: cfg.Timestamp,
+			redactor:  d.redactor,
+		}
+		d.drift[key] = e
+		events = append(events, e)
+	}
+	for key, prev := range d.drift {
+		if current[key] {
+			continue
+		}
+		delete(d.drift, key)
+		events = append(events, &DriftEvent{
+			Path:      prev.Path,
+			Intended:  prev.Intended,
+			Actual:    prev.Intended,
+			Resolved:  true,
+			Timestamp: cfg.Timestamp,
+			redactor:  d.redactor,
+		})
+	}
+	sortDriftEvents(events)
+	return events, nil
+}
+
+func sortDriftEvents(events []*DriftEvent) {
+	sort.Slice(events, func(i, j int) bool {
+		return pathString(events[i].Path) < pathString(events[j].Path)
+	})
+}
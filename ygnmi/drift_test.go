@@ -0,0 +1,97 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/ygnmi/exampleoc"
+	"github.com/openconfig/ygnmi/exampleoc/exampleocpath"
+	"github.com/openconfig/ygnmi/internal/testutil"
+	"github.com/openconfig/ygnmi/ygnmi"
+	"github.com/openconfig/ygot/ygot"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func TestDriftDetector(t *testing.T) {
+	fakeGNMI, c := newClient(t)
+	onePath := testutil.GNMIPath(t, "/parent/child/config/one")
+	threePath := testutil.GNMIPath(t, "/parent/child/config/three")
+	stringVal := func(s string) *gpb.TypedValue {
+		return &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: s}}
+	}
+	fakeGNMI.Stub().Notification(&gpb.Notification{
+		Timestamp: 100,
+		Update: []*gpb.Update{
+			{Path: onePath, Val: stringVal("foo")},
+			{Path: threePath, Val: stringVal("TWO")},
+		},
+	}).Sync().Notification(&gpb.Notification{
+		Timestamp: 101,
+		Update:    []*gpb.Update{{Path: onePath, Val: stringVal("bar")}},
+	}).Notification(&gpb.Notification{
+		Timestamp: 102,
+		Update: []*gpb.Update{
+			{Path: onePath, Val: stringVal("foo")},
+			{Path: threePath, Val: stringVal("ONE")},
+		},
+	})
+
+	d, err := ygnmi.NewDriftDetector(c, exampleocpath.Root().Parent().Child().Config(), &exampleoc.Parent_Child{
+		One:   ygot.String("foo"),
+		Three: exampleoc.Child_Three_ONE,
+	})
+	if err != nil {
+		t.Fatalf("NewDriftDetector() returned unexpected error: %v", err)
+	}
+	var got []string
+	var drift [][]string
+	d.Start(context.Background(), func(e *ygnmi.DriftEvent) error {
+		got = append(got, e.String())
+		var current []string
+		for _, e := range d.Drift() {
+			current = append(current, e.String())
+		}
+		drift = append(drift, current)
+		return nil
+	})
+	if err := d.Await(); !errors.Is(err, io.EOF) {
+		t.Fatalf("Await() returned unexpected error: %v", err)
+	}
+
+	want := []string{
+		`/parent/child/config/three: intended "ONE", actual "TWO"`,
+		`/parent/child/config/one: intended "foo", actual "bar"`,
+		`/parent/child/config/one: resolved`,
+		`/parent/child/config/three: resolved`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("DriftDetector emitted unexpected events (-want,+got):\n%s", diff)
+	}
+	wantDrift := [][]string{
+		{`/parent/child/config/three: intended "ONE", actual "TWO"`},
+		{`/parent/child/config/one: intended "foo", actual "bar"`, `/parent/child/config/three: intended "ONE", actual "TWO"`},
+		nil,
+		nil,
+	}
+	if diff := cmp.Diff(wantDrift, drift); diff != "" {
+		t.Errorf("Drift() returned unexpected drift (-want,+got):\n%s", diff)
+	}
+}
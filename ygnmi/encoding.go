@@ -0,0 +1,129 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+var (
+	// subscribeEncodings are the encodings usable for Subscribe requests, in order of preference.
+	subscribeEncodings = []gpb.Encoding{gpb.Encoding_PROTO, gpb.Encoding_JSON_IETF}
+	// getEncodings are the encodings usable for Get requests, in order of preference.
+	getEncodings = []gpb.Encoding{gpb.Encoding_JSON_IETF, gpb.Encoding_PROTO}
+	// setEncodings are the encodings usable for Set requests, in order of preference.
+	setEncodings = []gpb.Encoding{gpb.Encoding_JSON_IETF, gpb.Encoding_JSON}
+)
+
+// WithEncodingNegotiation enables choosing the encoding of each operation from the encodings supported by the target.
+// The capabilities of the target are fetched with a Capabilities request before the first operation that needs them,
+// and cached for the lifetime of the client.
+// Subscribe requests prefer PROTO then JSON_IETF, Get requests (see WithUseGet) prefer JSON_IETF then PROTO,
+// and Set requests prefer JSON_IETF then JSON.
+// An encoding set explicitly with WithEncoding is used as is, if the target supports it.
+// For Set requests, the explicit encoding must also be one of the Set encodings, PROTO is not usable.
+// WithSetPreferProtoEncoding is honored if the target supports PROTO and the Set encoding is JSON_IETF,
+// which it uses for non-scalar values. Otherwise, the values are encoded with the Set encoding.
+// If the target doesn't support the explicit encoding, or any of the encodings usable by the operation,
+// the operation fails before sending the request.
+func WithEncodingNegotiation() ClientOption {
+	return func(c *Client) error {
+		c.encodings = &encodingNegotiator{}
+		return nil
+	}
+}
+
+// encodingNegotiator caches the encodings supported by the target.
+type encodingNegotiator struct {
+	mu        sync.Mutex
+	fetched   bool
+	encodings []gpb.Encoding
+}
+
+// supported returns the encodings supported by the target, fetching them on the first call.
+// If fetching them fails, they are fetched again on the next call.
+func (n *encodingNegotiator) supported(ctx context.Context, c *Client) ([]gpb.Encoding, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.fetched {
+		return n.encodings, nil
+	}
+	caps, err := c.Capabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.log.info(ctx, 1, "Target supports encodings", "encodings", caps.Encodings)
+	n.encodings = caps.Encodings
+	n.fetched = true
+	return n.encodings, nil
+}
+
+// encodingFor returns the encoding to use for an operation. If negotiation is disabled, enc is returned.
+// Otherwise, if the encoding is explicit, it's checked that the target supports it,
+// or else the first encoding in preferred that the target supports is returned.
+func (c *Client) encodingFor(ctx context.Context, op string, enc gpb.Encoding, explicit bool, preferred []gpb.Encoding) (gpb.Encoding, error) {
+	if c.encodings == nil {
+		return enc, nil
+	}
+	supported, err := c.encodings.supported(ctx, c)
+	if err != nil {
+		return 0, fmt.Errorf("failed to negotiate encoding: %w", err)
+	}
+	if explicit {
+		if !slices.Contains(supported, enc) {
+			return 0, fmt.Errorf("%s encoding %v is not supported by the target, supported encodings: %v", op, enc, supported)
+		}
+		return enc, nil
+	}
+	for _, p := range preferred {
+		if slices.Contains(supported, p) {
+			return p, nil
+		}
+	}
+	return 0, fmt.Errorf("target supports none of the %s encodings %v, supported encodings: %v", op, preferred, supported)
+}
+
+// setOpts returns the options with the encoding negotiated for a Set request appended.
+// The preference for PROTO encoding is dropped if the target can't decode the values it produces.
+func (c *Client) setOpts(ctx context.Context, opts []Option) ([]Option, error) {
+	if c.encodings == nil {
+		return opts, nil
+	}
+	o := resolveOpts(opts)
+	enc, err := c.encodingFor(ctx, "Set", o.encoding, o.encodingSet, setEncodings)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(setEncodings, enc) {
+		return nil, fmt.Errorf("Set encoding %v is not usable, Set encodings: %v", enc, setEncodings)
+	}
+	opts = append(slices.Clip(opts), WithEncoding(enc))
+	if o.preferProto {
+		supported, err := c.encodings.supported(ctx, c)
+		if err != nil {
+			return nil, fmt.Errorf("failed to negotiate encoding: %w", err)
+		}
+		if !slices.Contains(supported, gpb.Encoding_PROTO) || enc != gpb.Encoding_JSON_IETF {
+			c.log.info(ctx, 2, "Not preferring PROTO encoding for Set", "encoding", enc, "supported", supported)
+			opts = append(opts, func(o *opt) { o.preferProto = false })
+		}
+	}
+	return opts, nil
+}
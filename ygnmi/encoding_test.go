@@ -0,0 +1,215 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/ygnmi/exampleoc"
+	"github.com/openconfig/ygnmi/exampleoc/exampleocpath"
+	"github.com/openconfig/ygnmi/ygnmi"
+	"github.com/openconfig/ygot/ygot"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// encodingServer is a gNMI server that advertises a set of encodings,
+// and records the encoding of the requests it receives.
+type encodingServer struct {
+	gpb.UnimplementedGNMIServer
+	encodings []gpb.Encoding
+
+	mu        sync.Mutex
+	capsCalls int
+	encoding  gpb.Encoding
+}
+
+func (s *encodingServer) Capabilities(context.Context, *gpb.CapabilityRequest) (*gpb.CapabilityResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.capsCalls++
+	return &gpb.CapabilityResponse{SupportedEncodings: s.encodings}, nil
+}
+
+func (s *encodingServer) Subscribe(srv gpb.GNMI_SubscribeServer) error {
+	req, err := srv.Recv()
+	if err != nil {
+		return err
+	}
+	s.record(req.GetSubscribe().GetEncoding())
+	return srv.Send(&gpb.SubscribeResponse{Response: &gpb.SubscribeResponse_SyncResponse{SyncResponse: true}})
+}
+
+func (s *encodingServer) Get(_ context.Context, req *gpb.GetRequest) (*gpb.GetResponse, error) {
+	s.record(req.GetEncoding())
+	return &gpb.GetResponse{}, nil
+}
+
+func (s *encodingServer) Set(_ context.Context, req *gpb.SetRequest) (*gpb.SetResponse, error) {
+	switch req.GetReplace()[0].GetVal().GetValue().(type) {
+	case *gpb.TypedValue_JsonIetfVal:
+		s.record(gpb.Encoding_JSON_IETF)
+	case *gpb.TypedValue_JsonVal:
+		s.record(gpb.Encoding_JSON)
+	default:
+		s.record(gpb.Encoding_PROTO)
+	}
+	return &gpb.SetResponse{}, nil
+}
+
+func (s *encodingServer) record(enc gpb.Encoding) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.encoding = enc
+}
+
+func TestEncodingNegotiation(t *testing.T) {
+	lq := exampleocpath.Root().RemoteContainer().ALeaf().State()
+	lookup := func(opts ...ygnmi.Option) func(*ygnmi.Client) error {
+		return func(c *ygnmi.Client) error {
+			_, err := ygnmi.Lookup(context.Background(), c, lq, opts...)
+			return err
+		}
+	}
+	replace := func(opts ...ygnmi.Option) func(*ygnmi.Client) error {
+		return func(c *ygnmi.Client) error {
+			_, err := ygnmi.Replace(context.Background(), c, exampleocpath.Root().Parent().Child().Config(), &exampleoc.Parent_Child{One: ygot.String("foo")}, opts...)
+			return err
+		}
+	}
+	replaceLeaf := func(opts ...ygnmi.Option) func(*ygnmi.Client) error {
+		return func(c *ygnmi.Client) error {
+			_, err := ygnmi.Replace(context.Background(), c, exampleocpath.Root().Parent().Child().One().Config(), "foo", opts...)
+			return err
+		}
+	}
+
+	tests := []struct {
+		desc         string
+		encodings    []gpb.Encoding
+		op           func(*ygnmi.Client) error
+		wantEncoding gpb.Encoding
+		wantErr      string
+	}{{
+		desc:         "subscribe prefers proto",
+		encodings:    []gpb.Encoding{gpb.Encoding_JSON_IETF, gpb.Encoding_PROTO},
+		op:           lookup(),
+		wantEncoding: gpb.Encoding_PROTO,
+	}, {
+		desc:         "subscribe without proto",
+		encodings:    []gpb.Encoding{gpb.Encoding_JSON, gpb.Encoding_JSON_IETF},
+		op:           lookup(),
+		wantEncoding: gpb.Encoding_JSON_IETF,
+	}, {
+		desc:         "subscribe explicit encoding",
+		encodings:    []gpb.Encoding{gpb.Encoding_PROTO, gpb.Encoding_JSON_IETF},
+		op:           lookup(ygnmi.WithEncoding(gpb.Encoding_JSON_IETF)),
+		wantEncoding: gpb.Encoding_JSON_IETF,
+	}, {
+		desc:      "subscribe explicit encoding not supported",
+		encodings: []gpb.Encoding{gpb.Encoding_JSON_IETF},
+		op:        lookup(ygnmi.WithEncoding(gpb.Encoding_PROTO)),
+		wantErr:   "Subscribe encoding PROTO is not supported by the target",
+	}, {
+		desc:      "subscribe no usable encoding",
+		encodings: []gpb.Encoding{gpb.Encoding_JSON},
+		op:        lookup(),
+		wantErr:   "target supports none of the Subscribe encodings",
+	}, {
+		desc:         "get prefers json ietf",
+		encodings:    []gpb.Encoding{gpb.Encoding_PROTO, gpb.Encoding_JSON_IETF},
+		op:           lookup(ygnmi.WithUseGet()),
+		wantEncoding: gpb.Encoding_JSON_IETF,
+	}, {
+		desc:         "get without json",
+		encodings:    []gpb.Encoding{gpb.Encoding_PROTO},
+		op:           lookup(ygnmi.WithUseGet()),
+		wantEncoding: gpb.Encoding_PROTO,
+	}, {
+		desc:         "set prefers json ietf",
+		encodings:    []gpb.Encoding{gpb.Encoding_JSON, gpb.Encoding_JSON_IETF},
+		op:           replace(),
+		wantEncoding: gpb.Encoding_JSON_IETF,
+	}, {
+		desc:         "set without json ietf",
+		encodings:    []gpb.Encoding{gpb.Encoding_PROTO, gpb.Encoding_JSON},
+		op:           replace(),
+		wantEncoding: gpb.Encoding_JSON,
+	}, {
+		desc:      "set explicit encoding not supported",
+		encodings: []gpb.Encoding{gpb.Encoding_JSON_IETF},
+		op:        replace(ygnmi.WithEncoding(gpb.Encoding_JSON)),
+		wantErr:   "Set encoding JSON is not supported by the target",
+	}, {
+		desc:         "set explicit encoding",
+		encodings:    []gpb.Encoding{gpb.Encoding_JSON_IETF, gpb.Encoding_JSON},
+		op:           replace(ygnmi.WithEncoding(gpb.Encoding_JSON)),
+		wantEncoding: gpb.Encoding_JSON,
+	}, {
+		desc:      "set explicit proto encoding",
+		encodings: []gpb.Encoding{gpb.Encoding_PROTO, gpb.Encoding_JSON_IETF},
+		op:        replace(ygnmi.WithEncoding(gpb.Encoding_PROTO)),
+		wantErr:   "Set encoding PROTO is not usable",
+	}, {
+		desc:         "set prefer proto",
+		encodings:    []gpb.Encoding{gpb.Encoding_JSON_IETF, gpb.Encoding_PROTO},
+		op:           replaceLeaf(ygnmi.WithSetPreferProtoEncoding()),
+		wantEncoding: gpb.Encoding_PROTO,
+	}, {
+		desc:         "set prefer proto non-scalar value",
+		encodings:    []gpb.Encoding{gpb.Encoding_JSON_IETF, gpb.Encoding_PROTO},
+		op:           replace(ygnmi.WithSetPreferProtoEncoding()),
+		wantEncoding: gpb.Encoding_JSON_IETF,
+	}, {
+		desc:         "set prefer proto not supported",
+		encodings:    []gpb.Encoding{gpb.Encoding_JSON_IETF},
+		op:           replaceLeaf(ygnmi.WithSetPreferProtoEncoding()),
+		wantEncoding: gpb.Encoding_JSON_IETF,
+	}, {
+		desc:         "set prefer proto without json ietf",
+		encodings:    []gpb.Encoding{gpb.Encoding_PROTO, gpb.Encoding_JSON},
+		op:           replaceLeaf(ygnmi.WithSetPreferProtoEncoding()),
+		wantEncoding: gpb.Encoding_JSON,
+	}, {
+		desc:      "set no usable encoding",
+		encodings: []gpb.Encoding{gpb.Encoding_PROTO},
+		op:        replace(),
+		wantErr:   "target supports none of the Set encodings",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			srv := &encodingServer{encodings: tt.encodings}
+			c := newServerClient(t, srv, ygnmi.WithEncodingNegotiation())
+			// Run the operation twice to check that the capabilities are cached.
+			for i := 0; i < 2; i++ {
+				err := tt.op(c)
+				if diff := errdiff.Substring(err, tt.wantErr); diff != "" {
+					t.Fatalf("operation returned unexpected diff: %s", diff)
+				}
+			}
+			srv.mu.Lock()
+			defer srv.mu.Unlock()
+			if srv.capsCalls != 1 {
+				t.Errorf("server got %d Capabilities RPCs, want 1", srv.capsCalls)
+			}
+			if tt.wantErr == "" && srv.encoding != tt.wantEncoding {
+				t.Errorf("request encoding got %v, want %v", srv.encoding, tt.wantEncoding)
+			}
+		})
+	}
+}
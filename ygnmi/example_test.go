@@ -135,6 +135,25 @@ func ExampleCollect() {
 	fmt.Printf("Got %d values", len(vals))
 }
 
+func ExampleStream() {
+	c := initClient()
+	path := exampleocpath.Root().Parent().Child()
+
+	// Use a context with a timeout, so that Stream doesn't continue indefinitely.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// Range over the values at path, breaking out of the loop closes the subscription.
+	for v, err := range ygnmi.Stream(ctx, c, path.State()) {
+		if err != nil {
+			log.Fatalf("Failed to Stream: %v", err)
+		}
+		if c, ok := v.Val(); ok && c.GetOne() == "good" {
+			break
+		}
+	}
+}
+
 func ExampleLookupAll() {
 	c := initClient()
 	// LookupAll on the keys of the SingleKey list
@@ -263,6 +282,34 @@ func ExampleWatch_batch() {
 	}
 }
 
+func ExampleSubscription_Watch() {
+	c := initClient()
+	// Use a context with a timeout, so that the subscription doesn't continue indefinitely.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// Add queries with different types and roots to a single subscription.
+	s := ygnmi.NewSubscription()
+	ygnmi.SubscriptionWatch(s, exampleocpath.Root().RemoteContainer().ALeaf().State(), func(v *ygnmi.Value[string]) error {
+		if val, ok := v.Val(); ok && val == "good" {
+			// Return nil to stop evaluating this query only.
+			return nil
+		}
+		return ygnmi.Continue
+	})
+	ygnmi.SubscriptionWatchAll(s, exampleocpath.Root().Model().SingleKeyAny().Value().State(), func(v *ygnmi.Value[int64]) error {
+		if val, ok := v.Val(); ok && val > 100 {
+			return nil
+		}
+		return ygnmi.Continue
+	})
+
+	// Await returns once all the predicates returned nil.
+	if err := s.Watch(ctx, c).Await(); err != nil {
+		log.Fatalf("Failed to Watch: %v", err)
+	}
+}
+
 func ExampleUpdate() {
 	c := initClient()
 	// Perform the Update request.
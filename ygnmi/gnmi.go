@@ -20,10 +20,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 	"time"
 
-	"github.com/openconfig/ygnmi/internal/logutil"
 	"github.com/openconfig/ygot/util"
 	"github.com/openconfig/ygot/ygot"
 	"google.golang.org/grpc/codes"
@@ -32,21 +32,22 @@ import (
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 
-	log "github.com/golang/glog"
 	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	gnmiextpb "github.com/openconfig/gnmi/proto/gnmi_ext"
 	closer "github.com/openconfig/gocloser"
 )
 
 // subscribe create a gNMI SubscribeClient for the given query.
-func subscribe[T any](ctx context.Context, c *Client, q AnyQuery[T], mode gpb.SubscriptionList_Mode, o *opt) (_ gpb.GNMI_SubscribeClient, rerr error) {
+func subscribe[T any](ctx context.Context, c *Client, q AnyQuery[T], mode gpb.SubscriptionList_Mode, o *opt) (gpb.GNMI_SubscribeClient, error) {
 	var queryPaths []*gpb.Path
-	var subs []*gpb.Subscription
-	for _, path := range q.subPaths() {
-		path, err := resolvePath(path)
+	var params []*SubscriptionParams
+	for _, ps := range q.subPaths() {
+		path, err := resolvePath(ps)
 		if err != nil {
 			return nil, err
 		}
 		queryPaths = append(queryPaths, path)
+		params = append(params, pathParams(ps))
 	}
 	if len(queryPaths) > 0 && o.ft != nil {
 		if !q.isLeaf() {
@@ -66,26 +67,61 @@ func subscribe[T any](ctx context.Context, c *Client, q AnyQuery[T], mode gpb.Su
 		}
 		match, inputs, err := o.ft.OutputToInput(schemaPath)
 		if err != nil {
-			log.ErrorContextf(ctx, "Received error from FunctionalTranslator.OutputToInput(): %v", err)
+			c.log.error(ctx, "Received error from FunctionalTranslator.OutputToInput()", "path", pathString(schemaPath), "error", err)
 			return nil, err
 		}
 		if !match {
 			return nil, fmt.Errorf("FunctionalTranslator.OutputToInput() did not match on path: %s", prototext.Format(schemaPath))
 		}
 
-		log.V(2).InfoContextf(ctx, "FunctionalTranslator.OutputToInput() mapped original query path %s to actual subscription paths: %+v", prototext.Format(queryPaths[0]), inputs)
+		c.log.info(ctx, 2, "FunctionalTranslator.OutputToInput() mapped the query path to the subscription paths", "path", pathString(queryPaths[0]), "inputs", pathStrings(inputs))
 		queryPaths = inputs
+		params = nil
+	}
+	if o.useGet && mode != gpb.SubscriptionList_ONCE {
+		return nil, fmt.Errorf("using gnmi.Get is only valid for ONCE subscriptions")
+	}
+
+	ctx = NewContext(ctx, q)
+	dt := gpb.GetRequest_CONFIG
+	if q.IsState() {
+		dt = gpb.GetRequest_STATE
 	}
+	return subscribePaths(ctx, c, q, queryPaths, params, mode, dt, o)
+}
 
-	for _, path := range queryPaths {
-		subs = append(subs, &gpb.Subscription{
+// subscribePaths creates a gNMI SubscribeClient for the given paths.
+// If params is not empty, it contains the subscription parameters for each path, which may be nil.
+// The dataType is only used when the subscription is done using gnmi.Get.
+// q is the query the subscription is made for, passed to the interceptors, and may be nil.
+func subscribePaths(ctx context.Context, c *Client, q UntypedQuery, paths []*gpb.Path, params []*SubscriptionParams, mode gpb.SubscriptionList_Mode, dataType gpb.GetRequest_DataType, o *opt) (_ gpb.GNMI_SubscribeClient, rerr error) {
+	var subs []*gpb.Subscription
+	for i, path := range paths {
+		sub := &gpb.Subscription{
 			Path: &gpb.Path{
 				Elem:   path.GetElem(),
 				Origin: path.GetOrigin(),
 			},
 			Mode:           o.mode,
 			SampleInterval: o.sampleInterval,
-		})
+		}
+		if i < len(params) && params[i] != nil {
+			params[i].apply(sub)
+		}
+		subs = append(subs, sub)
+	}
+	if err := checkHistory(o, mode); err != nil {
+		return nil, err
+	}
+	var encoding gpb.Encoding
+	var err error
+	if o.useGet {
+		encoding, err = c.encodingFor(ctx, "Get", gpb.Encoding_JSON_IETF, false, getEncodings)
+	} else {
+		encoding, err = c.encodingFor(ctx, "Subscribe", o.encoding, o.encodingSet, subscribeEncodings)
+	}
+	if err != nil {
+		return nil, err
 	}
 
 	sr := &gpb.SubscribeRequest{
@@ -96,38 +132,68 @@ func subscribe[T any](ctx context.Context, c *Client, q AnyQuery[T], mode gpb.Su
 				},
 				Subscription: subs,
 				Mode:         mode,
-				Encoding:     o.encoding,
+				Encoding:     encoding,
 			},
 		},
 	}
-	if o.useGet && mode != gpb.SubscriptionList_ONCE {
-		return nil, fmt.Errorf("using gnmi.Get is only valid for ONCE subscriptions")
+	if o.history != nil {
+		sr.Extension = []*gnmiextpb.Extension{{
+			Ext: &gnmiextpb.Extension_History{History: o.history},
+		}}
+	}
+	// History ranges are finite, so they are not shared with watchers that may join after they ended.
+	if c.subMgr != nil && mode == gpb.SubscriptionList_STREAM && !o.useGet && o.history == nil {
+		sub, err := c.subMgr.subscribe(ctx, c, &RPCInfo{Method: "Subscribe", Query: q}, sr)
+		if err != nil {
+			return nil, err
+		}
+		return c.telemetry.countNotifications(sub), nil
 	}
-
-	ctx = NewContext(ctx, q)
 
 	var sub gpb.GNMI_SubscribeClient
-	var err error
 	if o.useGet {
-		dt := gpb.GetRequest_CONFIG
-		if q.IsState() {
-			dt = gpb.GetRequest_STATE
-		}
 		sub = &getSubscriber{
 			client:   c,
 			ctx:      ctx,
-			dataType: dt,
+			query:    q,
+			dataType: dataType,
+			encoding: encoding,
+		}
+		if err := sendSubscribeRequest(sub, sr); err != nil {
+			return nil, err
 		}
 	} else {
-		sub, err = c.gnmiC.Subscribe(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("gNMI failed to Subscribe: %w", err)
+		if sub, err = c.newStream(ctx, &RPCInfo{Method: "Subscribe", Query: q}, sr); err != nil {
+			return nil, err
 		}
 	}
-	defer closer.Close(&rerr, sub.CloseSend, "error closing gNMI send stream")
-	if !o.useGet {
-		log.V(c.requestLogLevel).InfoContext(ctx, prototext.Format(sr))
+	if mode != gpb.SubscriptionList_POLL {
+		// Poll triggers are sent on the stream after the subscription is created.
+		defer closer.Close(&rerr, sub.CloseSend, "error closing gNMI send stream")
 	}
+	return c.telemetry.countNotifications(sub), nil
+}
+
+// checkHistory returns an error if the history request in the options can't be used with the subscription mode.
+// Per the history extension, snapshots are requested with ONCE subscriptions and ranges with STREAM subscriptions.
+func checkHistory(o *opt, mode gpb.SubscriptionList_Mode) error {
+	switch {
+	case o.history == nil:
+		return nil
+	case o.useGet:
+		return fmt.Errorf("history requests are not supported with gNMI Get")
+	case o.history.GetSnapshotTime() != 0 && mode != gpb.SubscriptionList_ONCE:
+		return fmt.Errorf("history snapshot is only valid for ONCE subscriptions, got %v", mode)
+	case o.history.GetRange() != nil && mode != gpb.SubscriptionList_STREAM:
+		return fmt.Errorf("history range is only valid for STREAM subscriptions, got %v", mode)
+	case o.history.GetRange() != nil && o.resubscribe != nil:
+		return fmt.Errorf("history range can't be resubscribed")
+	}
+	return nil
+}
+
+// sendSubscribeRequest sends the SubscribeRequest on the subscription.
+func sendSubscribeRequest(sub gpb.GNMI_SubscribeClient, sr *gpb.SubscribeRequest) error {
 	if err := sub.Send(sr); err != nil {
 		// If the server closes the RPC with an error, the real error may only be visible on Recv.
 		// https://pkg.go.dev/google.golang.org/grpc?utm_source=godoc#ClientStream
@@ -136,10 +202,9 @@ func subscribe[T any](ctx context.Context, c *Client, q AnyQuery[T], mode gpb.Su
 				err = recvErr
 			}
 		}
-		return nil, fmt.Errorf("gNMI failed to Send(%+v): %w", sr, err)
+		return fmt.Errorf("gNMI failed to Send(%+v): %w", sr, err)
 	}
-
-	return sub, nil
+	return nil
 }
 
 // getSubscriber is an implementation of gpb.GNMI_SubscribeClient that uses gpb.Get.
@@ -148,22 +213,27 @@ type getSubscriber struct {
 	gpb.GNMI_SubscribeClient
 	client   *Client
 	ctx      context.Context
+	query    UntypedQuery
 	notifs   []*gpb.Notification
 	dataType gpb.GetRequest_DataType
+	encoding gpb.Encoding
 }
 
 // Send call gnmi.Get with a request equivalent to the SubscribeRequest.
 func (gs *getSubscriber) Send(req *gpb.SubscribeRequest) error {
 	getReq := &gpb.GetRequest{
 		Prefix:   req.GetSubscribe().GetPrefix(),
-		Encoding: gpb.Encoding_JSON_IETF,
+		Encoding: gs.encoding,
 		Type:     gs.dataType,
 	}
 	for _, sub := range req.GetSubscribe().GetSubscription() {
 		getReq.Path = append(getReq.Path, sub.GetPath())
 	}
-	log.V(gs.client.requestLogLevel).Info(prototext.Format(getReq))
-	resp, err := gs.client.gnmiC.Get(gs.ctx, getReq)
+	// The request is logged after the interceptors, like Set and Subscribe requests.
+	resp, err := unaryResponse[*gpb.GetResponse](gs.client.invokeUnary(gs.ctx, &RPCInfo{Method: "Get", Query: gs.query}, getReq, func(ctx context.Context, req proto.Message) (proto.Message, error) {
+		gs.client.log.request(ctx, "Get", req)
+		return unaryInvoker(gs.client.gnmiC.Get)(ctx, req)
+	}))
 	if st, ok := status.FromError(err); ok && st.Code() == codes.NotFound { // Make this behave like Subscribe, where non-existent paths don't return values.
 		return nil
 	} else if err != nil {
@@ -198,24 +268,30 @@ func (gs *getSubscriber) CloseSend() error {
 // the data is returned as-is and the second return value is true. If Delete paths are present in
 // the update, they are appended to the given data before the Update values. If deletesExpected
 // is false, however, any deletes received will cause an error.
-func receive(sub gpb.GNMI_SubscribeClient, data []*DataPoint, deletesExpected bool, queryPath *gpb.Path, o *opt) ([]*DataPoint, bool, error) {
+func receive(ctx context.Context, sub gpb.GNMI_SubscribeClient, data []*DataPoint, deletesExpected bool, queryPath *gpb.Path, o *opt) ([]*DataPoint, bool, error) {
 	res, err := sub.Recv()
 	if err != nil {
 		return data, false, err
 	}
 	recvTS := time.Now()
+	// The arguments of the debug logs are only built if they are logged.
+	verbose := o.log.enabled(ctx, 2)
 
 	if o.ft != nil {
 		out, err := o.ft.Translate(res)
 		if err != nil {
-			log.Errorf("FunctionalTranslator.Translate() failed to translate notification: %v", err)
+			o.log.error(ctx, "FunctionalTranslator.Translate() failed to translate notification", "error", err)
 			return data, false, nil
 		}
 		if out == nil {
-			log.V(2).Infof("Received nil response from functional translatator with input: %s", prototext.Format(res))
+			if verbose {
+				o.log.info(ctx, 2, "Received nil response from functional translator", "input", compactText(o.log.redactor().message(res)))
+			}
 			return data, false, nil
 		}
-		log.V(2).Infof("FT successfully translated a notification. input: %s, output: %s", prototext.Format(res), prototext.Format(out))
+		if verbose {
+			o.log.info(ctx, 2, "FT successfully translated a notification", "input", compactText(o.log.redactor().message(res)), "output", compactText(o.log.redactor().message(out)))
+		}
 		res = out
 	}
 
@@ -239,25 +315,31 @@ func receive(sub gpb.GNMI_SubscribeClient, data []*DataPoint, deletesExpected bo
 			}
 			// Use the target only for the subscription but exclude from the datapoint construction.
 			j.Target = ""
-			return &DataPoint{Path: j, Value: val, Timestamp: ts, RecvTimestamp: recvTS}, nil
+			return &DataPoint{Path: j, Value: val, Timestamp: ts, RecvTimestamp: recvTS, redactor: o.log.redactor()}, nil
 		}
 
 		// Append delete data before the update values -- per gNMI spec, they
 		// should always be processed first if both update types exist in the
 		// same notification.
 		for _, p := range n.Delete {
-			log.V(2).Infof("Received gNMI Delete at path: %s", prototext.Format(p))
+			if verbose {
+				o.log.info(ctx, 2, "Received gNMI Delete", "path", pathString(p))
+			}
 			dp, err := newDataPoint(p, nil)
 			if err != nil {
 				return data, false, err
 			}
-			log.V(2).Infof("Constructed datapoint for delete: %v", dp)
+			if verbose {
+				o.log.info(ctx, 2, "Constructed datapoint for delete", "datapoint", dp.String())
+			}
 			// Filter out paths that don't match the query here as a workaround for the edge case where we
 			// query a path including a specific key and the FT subscribes to more data than just that.
 			// This extra data would otherwise be filtered out downstream but with a compliance error.
 			// This uses the same logic that unmarshal(...) in unmarshal.go uses to check compliance.
 			if o.ft != nil && !util.PathMatchesQuery(dp.Path, queryPath) {
-				log.V(2).Infof("Skipping delete datapoint that doesn't match the query. path: %s, query: %s", prototext.Format(dp.Path), prototext.Format(queryPath))
+				if verbose {
+					o.log.info(ctx, 2, "Skipping delete datapoint that doesn't match the query", "path", pathString(dp.Path), "query", pathString(queryPath))
+				}
 				continue
 			}
 			data = append(data, dp)
@@ -269,21 +351,27 @@ func receive(sub gpb.GNMI_SubscribeClient, data []*DataPoint, deletesExpected bo
 			if u.Val == nil {
 				return data, false, fmt.Errorf("invalid nil Val in update: %v", u)
 			}
-			log.V(2).Infof("Received gNMI Update value %s at path: %s", prototext.Format(u.Val), prototext.Format(u.Path))
+			if verbose {
+				o.log.info(ctx, 2, "Received gNMI Update", "path", pathString(u.Path), "value", compactText(o.log.redactor().typedValue(u.Val, n.GetPrefix(), u.Path)))
+			}
 			dp, err := newDataPoint(u.Path, u.Val)
 			if err != nil {
 				return data, false, err
 			}
-			log.V(2).Infof("Constructed datapoint for update: %v", dp)
+			if verbose {
+				o.log.info(ctx, 2, "Constructed datapoint for update", "datapoint", dp.String())
+			}
 			if o.ft != nil && !util.PathMatchesQuery(dp.Path, queryPath) {
-				log.V(2).Infof("Skipping update datapoint that doesn't match the query. path: %s, query: %s", prototext.Format(dp.Path), prototext.Format(queryPath))
+				if verbose {
+					o.log.info(ctx, 2, "Skipping update datapoint that doesn't match the query", "path", pathString(dp.Path), "query", pathString(queryPath))
+				}
 				continue
 			}
 			data = append(data, dp)
 		}
 		return data, false, nil
 	case *gpb.SubscribeResponse_SyncResponse:
-		log.V(2).Infof("Received gNMI SyncResponse.")
+		o.log.info(ctx, 2, "Received gNMI SyncResponse")
 		data = append(data, &DataPoint{
 			RecvTimestamp: recvTS,
 			Sync:          true,
@@ -296,20 +384,25 @@ func receive(sub gpb.GNMI_SubscribeClient, data []*DataPoint, deletesExpected bo
 
 // receiveAll receives data until the context deadline is reached, or when a sync response is received.
 // This func is only used when receiving data from a ONCE subscription.
-func receiveAll[T any](sub gpb.GNMI_SubscribeClient, deletesExpected bool, query AnyQuery[T], o *opt) (data []*DataPoint, err error) {
+func receiveAll[T any](ctx context.Context, sub gpb.GNMI_SubscribeClient, deletesExpected bool, query AnyQuery[T], o *opt) (data []*DataPoint, err error) {
 	queryPath, err := resolvePath(query.PathStruct())
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve path: %w", err)
 	}
+	return receiveUntilSync(ctx, sub, deletesExpected, queryPath, o)
+}
+
+// receiveUntilSync is receiveAll for a subscription to queryPath that isn't made for a query.
+func receiveUntilSync(ctx context.Context, sub gpb.GNMI_SubscribeClient, deletesExpected bool, queryPath *gpb.Path, o *opt) (data []*DataPoint, err error) {
 	for {
 		var sync bool
-		data, sync, err = receive(sub, data, deletesExpected, queryPath, o)
+		data, sync, err = receive(ctx, sub, data, deletesExpected, queryPath, o)
 		if err != nil {
 			if err == io.EOF {
 				// TODO(wenbli): It is unclear whether "subscribe ONCE stream closed without sync_response"
 				// should be an error, so tolerate both scenarios.
 				// See https://github.com/openconfig/reference/pull/156
-				log.V(1).Infof("subscribe ONCE stream closed without sync_response.")
+				o.log.info(ctx, 1, "subscribe ONCE stream closed without sync_response")
 				break
 			}
 			// DeadlineExceeded is expected when collections are complete.
@@ -329,7 +422,9 @@ func receiveAll[T any](sub gpb.GNMI_SubscribeClient, deletesExpected bool, query
 // Note: this does not imply that mode is gpb.SubscriptionList_STREAM (though it usually is).
 // If the query is a leaf, each datapoint will be sent the chan individually.
 // If the query is a non-leaf, all the datapoints from a SubscriptionResponse are bundled.
-func receiveStream[T any](ctx context.Context, sub gpb.GNMI_SubscribeClient, query AnyQuery[T], o *opt) (<-chan []*DataPoint, <-chan error) {
+// If a resubscribe policy is set, the subscription is reopened when the stream fails,
+// see WithResubscribe for details.
+func receiveStream[T any](ctx context.Context, c *Client, sub gpb.GNMI_SubscribeClient, query AnyQuery[T], o *opt) (<-chan []*DataPoint, <-chan error) {
 	dataCh := make(chan []*DataPoint)
 	errCh := make(chan error)
 
@@ -341,6 +436,12 @@ func receiveStream[T any](ctx context.Context, sub gpb.GNMI_SubscribeClient, que
 		var hasSynced bool
 		var sync bool
 		var err error
+		// resyncing is true while the initial sync of a reopened subscription is received.
+		var resyncing bool
+		rs := newResubscriber(o.resubscribe, c.log)
+		subFn := func() (gpb.GNMI_SubscribeClient, error) {
+			return subscribe(ctx, c, query, gpb.SubscriptionList_STREAM, o)
+		}
 
 		queryPath, err := resolvePath(query.PathStruct())
 		if err != nil {
@@ -348,7 +449,15 @@ func receiveStream[T any](ctx context.Context, sub gpb.GNMI_SubscribeClient, que
 			return
 		}
 		for {
-			recvData, sync, err = receive(sub, recvData, true, queryPath, o)
+			recvData, sync, err = receive(ctx, sub, recvData, true, queryPath, o)
+			if err != nil && rs.shouldRetry(ctx, err) {
+				sub, err = rs.resubscribe(ctx, subFn, err)
+				if err == nil {
+					recvData = nil
+					resyncing = true
+					continue
+				}
+			}
 			if err != nil {
 				// In the case that the context is cancelled, the reader of errCh
 				// may have gone away. In order to avoid this goroutine blocking
@@ -360,6 +469,16 @@ func receiveStream[T any](ctx context.Context, sub gpb.GNMI_SubscribeClient, que
 				}
 				return
 			}
+			if resyncing {
+				// Buffer the initial sync of the reopened subscription, so that state that
+				// disappeared while the stream was down can be removed along with it.
+				if !sync {
+					continue
+				}
+				recvData = rs.resync(recvData)
+				rs.reset()
+				resyncing = false
+			}
 			firstSync := !hasSynced && (sync || query.isLeaf())
 			hasSynced = hasSynced || sync || query.isLeaf()
 			// Skip conversion and predicate until first sync for non-leaves.
@@ -378,14 +497,132 @@ func receiveStream[T any](ctx context.Context, sub gpb.GNMI_SubscribeClient, que
 				datas = [][]*DataPoint{recvData}
 			}
 			for _, data := range datas {
-				dataCh <- data
+				// The reader of dataCh may have gone away if the context is cancelled.
+				select {
+				case dataCh <- data:
+				case <-ctx.Done():
+					return
+				}
 			}
+			rs.record(recvData)
 			recvData = nil
 		}
 	}()
 	return dataCh, errCh
 }
 
+// resubscriber reopens a failed STREAM subscription according to a ResubscribePolicy.
+// It tracks the paths of the values received on the subscription,
+// so that values deleted while the subscription was down can be detected.
+type resubscriber struct {
+	policy   *ResubscribePolicy
+	attempts int
+	backoff  time.Duration
+	paths    map[string]*gpb.Path
+	log      *logger
+}
+
+// newResubscriber returns a resubscriber for the policy, or nil if the policy is nil.
+func newResubscriber(policy *ResubscribePolicy, l *logger) *resubscriber {
+	if policy == nil {
+		return nil
+	}
+	return &resubscriber{
+		policy: policy,
+		paths:  map[string]*gpb.Path{},
+		log:    l,
+	}
+}
+
+// shouldRetry returns whether the subscription should be reopened after it failed with err.
+func (r *resubscriber) shouldRetry(ctx context.Context, err error) bool {
+	return r != nil && ctx.Err() == nil && r.policy.retryable(err)
+}
+
+// resubscribe reopens the subscription using subFn after it failed with cause,
+// waiting between attempts according to the policy. The attempts are counted
+// until reset is called, so that a subscription that fails before syncing
+// is not retried indefinitely.
+func (r *resubscriber) resubscribe(ctx context.Context, subFn func() (gpb.GNMI_SubscribeClient, error), cause error) (gpb.GNMI_SubscribeClient, error) {
+	for r.policy.MaxAttempts == 0 || r.attempts < r.policy.MaxAttempts {
+		if r.attempts == 0 {
+			r.backoff = r.policy.initialBackoff()
+		} else {
+			r.backoff = r.policy.nextBackoff(r.backoff)
+		}
+		r.attempts++
+		r.log.warning(ctx, "Subscription failed, resubscribing", "backoff", r.backoff, "attempt", r.attempts, "error", cause)
+		select {
+		case <-time.After(r.backoff):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		sub, err := subFn()
+		if err == nil {
+			return sub, nil
+		}
+		if !r.shouldRetry(ctx, err) {
+			return nil, err
+		}
+		cause = err
+	}
+	return nil, fmt.Errorf("giving up after %d resubscribe attempts: %w", r.attempts, cause)
+}
+
+// reset resets the attempts after the reopened subscription has synced.
+func (r *resubscriber) reset() {
+	r.attempts = 0
+}
+
+// record updates the known paths with the datapoints delivered to the caller.
+// A nil receiver is a noop.
+func (r *resubscriber) record(data []*DataPoint) {
+	if r == nil {
+		return
+	}
+	for _, dp := range data {
+		if dp.Sync {
+			continue
+		}
+		if dp.Value != nil {
+			r.paths[pathToString(dp.Path)] = dp.Path
+			continue
+		}
+		for key, p := range r.paths {
+			if util.PathMatchesPathElemPrefix(p, dp.Path) {
+				delete(r.paths, key)
+			}
+		}
+	}
+}
+
+// resync returns the datapoints of the initial sync of a reopened subscription,
+// prefixed with deletes for all known paths that are no longer present.
+func (r *resubscriber) resync(data []*DataPoint) []*DataPoint {
+	var deletes []*DataPoint
+	var recvTS time.Time
+	if len(data) > 0 {
+		recvTS = data[len(data)-1].RecvTimestamp
+	}
+	for key, p := range r.paths {
+		var present bool
+		for _, dp := range data {
+			if !dp.Sync && dp.Value != nil && util.PathMatchesPathElemPrefix(p, dp.Path) {
+				present = true
+				break
+			}
+		}
+		if !present {
+			r.log.info(context.Background(), 2, "Path not present after resubscribe, deleting it", "path", key)
+			deletes = append(deletes, &DataPoint{Path: p, RecvTimestamp: recvTS})
+		}
+	}
+	sort.Slice(deletes, func(i, j int) bool {
+		return pathToString(deletes[i].Path) < pathToString(deletes[j].Path)
+	})
+	return append(deletes, data...)
+}
+
 // removeRedundantModPrefix7951 removes any modName prefixes in the first layer
 // of the input JSON v.
 //
@@ -463,11 +700,22 @@ func wrapJSONIETF(tv *gpb.TypedValue, qualifiedRelPath []string) error {
 }
 
 // set configures the target at the query path.
-func set[T any](ctx context.Context, c *Client, q ConfigQuery[T], val T, op setOperation, opts ...Option) (*gpb.SetResponse, *gpb.Path, error) {
+func set[T any](ctx context.Context, c *Client, q ConfigQuery[T], val T, op setOperation, opts ...Option) (*Result, *gpb.Path, error) {
 	path, err := resolvePath(q.PathStruct())
 	if err != nil {
 		return nil, nil, err
 	}
+	opts, err = c.setOpts(ctx, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The value of a delete isn't sent, so it isn't validated.
+	if o := resolveOpts(opts); o.validateSet && op != deletePath {
+		if errs := validateSetValue(q, path, val, o.validationOpts); errs != nil {
+			return nil, path, errs
+		}
+	}
 
 	req := &gpb.SetRequest{}
 	var setVal interface{} = val
@@ -481,11 +729,8 @@ func set[T any](ctx context.Context, c *Client, q ConfigQuery[T], val T, op setO
 	req.Prefix = &gpb.Path{
 		Target: c.target,
 	}
-	logutil.LogByLine(c.requestLogLevel, prettySetRequest(req))
-	resp, err := c.gnmiC.Set(ctx, req)
-	log.V(c.requestLogLevel).Infof("SetResponse:\n%s", prototext.Format(resp))
-
-	return resp, path, err
+	res, err := c.sendSet(ctx, req, []*OperationResult{{Path: path, Op: op.updateResultOp(), Query: q}}, opts)
+	return res, path, err
 }
 
 // setOperation is an enum representing the different kinds of SetRequest
@@ -499,6 +744,22 @@ const (
 	unionreplacePath
 )
 
+// updateResultOp returns the operation of the UpdateResult of the operation.
+func (op setOperation) updateResultOp() gpb.UpdateResult_Operation {
+	switch op {
+	case deletePath:
+		return gpb.UpdateResult_DELETE
+	case replacePath:
+		return gpb.UpdateResult_REPLACE
+	case updatePath:
+		return gpb.UpdateResult_UPDATE
+	case unionreplacePath:
+		return gpb.UpdateResult_UNION_REPLACE
+	default:
+		return gpb.UpdateResult_INVALID
+	}
+}
+
 // populateSetRequest fills a SetResponse for a val and operation type.
 func populateSetRequest(req *gpb.SetRequest, path *gpb.Path, val interface{}, op setOperation, preferShadowPath, isLeaf bool, compressInfo *CompressionInfo, opts ...Option) error {
 	if req == nil {
@@ -512,7 +773,9 @@ func populateSetRequest(req *gpb.SetRequest, path *gpb.Path, val interface{}, op
 	case replacePath, updatePath, unionreplacePath:
 		var typedVal *gpb.TypedValue
 		var err error
-		if s, ok := val.(*string); ok && path.Origin == "cli" {
+		if tv, ok := val.(*gpb.TypedValue); ok {
+			typedVal = tv
+		} else if s, ok := val.(*string); ok && path.Origin == "cli" {
 			typedVal = &gpb.TypedValue{Value: &gpb.TypedValue_AsciiVal{AsciiVal: *s}}
 		} else if s, ok := val.(string); ok && strings.HasSuffix(path.Origin, "_cli") {
 			typedVal = &gpb.TypedValue{Value: &gpb.TypedValue_AsciiVal{AsciiVal: s}}
@@ -632,6 +895,9 @@ const (
 )
 
 func resolvePath(q PathStruct) (*gpb.Path, error) {
+	if pp, ok := q.(*paramsPath); ok {
+		q = pp.PathStruct
+	}
 	path, opts, err := ResolvePath(q)
 	if err != nil {
 		return nil, err
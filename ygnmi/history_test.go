@@ -0,0 +1,171 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/ygnmi/exampleoc/exampleocpath"
+	"github.com/openconfig/ygnmi/internal/gnmitestutil"
+	"github.com/openconfig/ygnmi/internal/testutil"
+	"github.com/openconfig/ygnmi/ygnmi"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	gnmiextpb "github.com/openconfig/gnmi/proto/gnmi_ext"
+)
+
+// verifyHistoryRequest verifies that the subscription request contains the history extension.
+func verifyHistoryRequest(t *testing.T, fakeGNMI *gnmitestutil.FakeGNMI, want *gnmiextpb.History) {
+	t.Helper()
+	requests := fakeGNMI.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("Number of subscription requests got %d, want 1", len(requests))
+	}
+	wantExt := []*gnmiextpb.Extension{{Ext: &gnmiextpb.Extension_History{History: want}}}
+	if diff := cmp.Diff(wantExt, requests[0].GetExtension(), protocmp.Transform()); diff != "" {
+		t.Errorf("Subscription request extensions returned unexpected diff (-want,+got):\n%s", diff)
+	}
+}
+
+func TestHistorySnapshot(t *testing.T) {
+	fakeGNMI, c := newClient(t)
+	leafPath := testutil.GNMIPath(t, "/remote-container/state/a-leaf")
+	lq := exampleocpath.Root().RemoteContainer().ALeaf().State()
+
+	fakeGNMI.Stub().Notification(&gpb.Notification{
+		Timestamp: 100,
+		Update: []*gpb.Update{{
+			Path: leafPath,
+			Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "foo"}},
+		}},
+	}).Sync()
+
+	got, err := ygnmi.Lookup(context.Background(), c, lq, ygnmi.WithHistorySnapshot(time.Unix(0, 150)))
+	if err != nil {
+		t.Fatalf("Lookup() returned unexpected error: %v", err)
+	}
+	want := (&ygnmi.Value[string]{
+		Path:      leafPath,
+		Timestamp: time.Unix(0, 100),
+	}).SetVal("foo")
+	want.RecvTimestamp = got.RecvTimestamp
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(ygnmi.Value[string]{}), protocmp.Transform()); diff != "" {
+		t.Errorf("Lookup() returned unexpected diff (-want,+got):\n%s", diff)
+	}
+	verifyHistoryRequest(t, fakeGNMI, &gnmiextpb.History{
+		Request: &gnmiextpb.History_SnapshotTime{SnapshotTime: 150},
+	})
+}
+
+func TestHistoryRange(t *testing.T) {
+	fakeGNMI, c := newClient(t)
+	key10Path := testutil.GNMIPath(t, "/model/a/single-key[key=10]/state/value")
+
+	fakeGNMI.Stub().Notification(&gpb.Notification{
+		Timestamp: 100,
+		Update: []*gpb.Update{{
+			Path: key10Path,
+			Val:  &gpb.TypedValue{Value: &gpb.TypedValue_IntVal{IntVal: 100}},
+		}},
+	}).Notification(&gpb.Notification{
+		Timestamp: 200,
+		Update: []*gpb.Update{{
+			Path: key10Path,
+			Val:  &gpb.TypedValue{Value: &gpb.TypedValue_IntVal{IntVal: 101}},
+		}},
+	}).Sync()
+
+	// The target closes the subscription after sending the range, so the collection
+	// ends before the context deadline.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	got, err := ygnmi.CollectAll(ctx, c, exampleocpath.Root().Model().SingleKeyAny().Value().State(), ygnmi.WithHistoryRange(time.Unix(0, 50), time.Unix(0, 250))).Await()
+	if err != nil {
+		t.Fatalf("CollectAll() returned unexpected error: %v", err)
+	}
+	var gotTS []int64
+	var gotVals []int64
+	for _, v := range got {
+		val, ok := v.Val()
+		if !ok {
+			t.Errorf("CollectAll() returned non-present value at %v", v.Path)
+		}
+		gotVals = append(gotVals, val)
+		gotTS = append(gotTS, v.Timestamp.UnixNano())
+	}
+	if diff := cmp.Diff([]int64{100, 101}, gotVals); diff != "" {
+		t.Errorf("CollectAll() returned unexpected values (-want,+got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int64{100, 200}, gotTS); diff != "" {
+		t.Errorf("CollectAll() returned unexpected timestamps (-want,+got):\n%s", diff)
+	}
+	verifyHistoryRequest(t, fakeGNMI, &gnmiextpb.History{
+		Request: &gnmiextpb.History_Range{Range: &gnmiextpb.TimeRange{Start: 50, End: 250}},
+	})
+}
+
+func TestHistoryErrors(t *testing.T) {
+	lq := exampleocpath.Root().RemoteContainer().ALeaf().State()
+	snapshot := ygnmi.WithHistorySnapshot(time.Unix(0, 100))
+	timeRange := ygnmi.WithHistoryRange(time.Unix(0, 100), time.Unix(0, 200))
+
+	tests := []struct {
+		desc    string
+		op      func(*ygnmi.Client) error
+		wantErr string
+	}{{
+		desc: "snapshot with stream",
+		op: func(c *ygnmi.Client) error {
+			_, err := ygnmi.Collect(context.Background(), c, lq, snapshot).Await()
+			return err
+		},
+		wantErr: "history snapshot is only valid for ONCE subscriptions",
+	}, {
+		desc: "range with once",
+		op: func(c *ygnmi.Client) error {
+			_, err := ygnmi.Lookup(context.Background(), c, lq, timeRange)
+			return err
+		},
+		wantErr: "history range is only valid for STREAM subscriptions",
+	}, {
+		desc: "range with resubscribe",
+		op: func(c *ygnmi.Client) error {
+			_, err := ygnmi.Collect(context.Background(), c, lq, timeRange, ygnmi.WithResubscribe(&ygnmi.ResubscribePolicy{})).Await()
+			return err
+		},
+		wantErr: "history range can't be resubscribed",
+	}, {
+		desc: "use get",
+		op: func(c *ygnmi.Client) error {
+			_, err := ygnmi.Lookup(context.Background(), c, lq, snapshot, ygnmi.WithUseGet())
+			return err
+		},
+		wantErr: "history requests are not supported with gNMI Get",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			_, c := newClient(t)
+			err := tt.op(c)
+			if diff := errdiff.Substring(err, tt.wantErr); diff != "" {
+				t.Errorf("operation returned unexpected diff: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// RPCInfo describes the gNMI RPC an interceptor is called for.
+type RPCInfo struct {
+	// Method is the name of the RPC: "Get", "Set" or "Subscribe".
+	Method string
+	// Query is the query the RPC is made for. It is nil for Set requests
+	// that don't have exactly one operation, for Set operations built from gNMI paths,
+	// and for the Subscribe RPC of a Subscription, which is shared by several queries.
+	// The subscribed paths are always in the SubscribeRequest.
+	Query UntypedQuery
+	// Operations are the operations of a Set request, without their responses.
+	Operations []*OperationResult
+}
+
+// UnaryInvoker sends a GetRequest or SetRequest and returns its response.
+type UnaryInvoker func(ctx context.Context, req proto.Message) (proto.Message, error)
+
+// UnaryInterceptor intercepts the Get and Set RPCs of a client.
+// req is a *gpb.GetRequest or a *gpb.SetRequest, and the returned response must be
+// the corresponding *gpb.GetResponse or *gpb.SetResponse.
+// The interceptor is responsible for calling invoker to continue the RPC, and may modify the
+// context, the request and the response.
+type UnaryInterceptor func(ctx context.Context, info *RPCInfo, req proto.Message, invoker UnaryInvoker) (proto.Message, error)
+
+// Streamer opens a subscription and sends the SubscribeRequest on it.
+type Streamer func(ctx context.Context, req *gpb.SubscribeRequest) (gpb.GNMI_SubscribeClient, error)
+
+// StreamInterceptor intercepts the Subscribe RPCs of a client, including the subscriptions
+// created by Lookup and Get unless WithUseGet is used.
+// The interceptor is responsible for calling streamer to continue the RPC, and may modify the
+// context and the request. To see each response, it may wrap the returned subscription,
+// whose Recv method is called for every SubscribeResponse.
+type StreamInterceptor func(ctx context.Context, info *RPCInfo, req *gpb.SubscribeRequest, streamer Streamer) (gpb.GNMI_SubscribeClient, error)
+
+// Interceptor is either a UnaryInterceptor or a StreamInterceptor.
+type Interceptor interface {
+	isInterceptor()
+}
+
+func (UnaryInterceptor) isInterceptor()  {}
+func (StreamInterceptor) isInterceptor() {}
+
+// WithInterceptors adds interceptors to the Get, Set and Subscribe RPCs made by the client.
+// Interceptors of each kind are called in order, the first one being the outermost.
+// Capabilities requests are not intercepted.
+//
+//	ygnmi.NewClient(gnmiC, ygnmi.WithInterceptors(
+//		ygnmi.UnaryInterceptor(func(ctx context.Context, info *ygnmi.RPCInfo, req proto.Message, invoker ygnmi.UnaryInvoker) (proto.Message, error) {
+//			return invoker(metadata.AppendToOutgoingContext(ctx, "user", "admin"), req)
+//		}),
+//	))
+func WithInterceptors(interceptors ...Interceptor) ClientOption {
+	return func(c *Client) error {
+		for _, i := range interceptors {
+			switch i := i.(type) {
+			case UnaryInterceptor:
+				c.unaryInterceptors = append(c.unaryInterceptors, i)
+			case StreamInterceptor:
+				c.streamInterceptors = append(c.streamInterceptors, i)
+			default:
+				return fmt.Errorf("unsupported interceptor type %T", i)
+			}
+		}
+		return nil
+	}
+}
+
+// invokeUnary calls the unary interceptors of the client, then the invoker.
+func (c *Client) invokeUnary(ctx context.Context, info *RPCInfo, req proto.Message, invoker UnaryInvoker) (proto.Message, error) {
+	for i := len(c.unaryInterceptors) - 1; i >= 0; i-- {
+		interceptor, next := c.unaryInterceptors[i], invoker
+		invoker = func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return interceptor(ctx, info, req, next)
+		}
+	}
+	return invoker(ctx, req)
+}
+
+// newStream calls the stream interceptors of the client, then opens the subscription
+// and sends the SubscribeRequest, logging the request as modified by the interceptors.
+func (c *Client) newStream(ctx context.Context, info *RPCInfo, req *gpb.SubscribeRequest) (gpb.GNMI_SubscribeClient, error) {
+	var streamer Streamer = func(ctx context.Context, req *gpb.SubscribeRequest) (gpb.GNMI_SubscribeClient, error) {
+		c.log.request(ctx, "Subscribe", req)
+		sub, err := c.gnmiC.Subscribe(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("gNMI failed to Subscribe: %w", err)
+		}
+		if err := sendSubscribeRequest(sub, req); err != nil {
+			return nil, err
+		}
+		return sub, nil
+	}
+	for i := len(c.streamInterceptors) - 1; i >= 0; i-- {
+		interceptor, next := c.streamInterceptors[i], streamer
+		streamer = func(ctx context.Context, req *gpb.SubscribeRequest) (gpb.GNMI_SubscribeClient, error) {
+			return interceptor(ctx, info, req, next)
+		}
+	}
+	return streamer(ctx, req)
+}
+
+// unaryInvoker returns an invoker calling a unary gNMI RPC.
+func unaryInvoker[Req, Resp proto.Message](rpc func(context.Context, Req, ...grpc.CallOption) (Resp, error)) UnaryInvoker {
+	return func(ctx context.Context, req proto.Message) (proto.Message, error) {
+		r, ok := req.(Req)
+		if !ok {
+			var want Req
+			return nil, fmt.Errorf("interceptor passed a %T request, want %T", req, want)
+		}
+		return rpc(ctx, r)
+	}
+}
+
+// unaryResponse returns the response returned by the interceptors as the type of the RPC response.
+func unaryResponse[Resp proto.Message](resp proto.Message, err error) (Resp, error) {
+	r, ok := resp.(Resp)
+	if !ok && resp != nil && err == nil {
+		return r, fmt.Errorf("interceptor returned a %T response, want %T", resp, r)
+	}
+	return r, err
+}
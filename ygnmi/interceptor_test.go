@@ -0,0 +1,221 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/ygnmi/exampleoc/exampleocpath"
+	"github.com/openconfig/ygnmi/internal/testutil"
+	"github.com/openconfig/ygnmi/ygnmi"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// countingSubscriber counts the responses received on a subscription.
+type countingSubscriber struct {
+	gpb.GNMI_SubscribeClient
+	count *int
+}
+
+func (s *countingSubscriber) Recv() (*gpb.SubscribeResponse, error) {
+	resp, err := s.GNMI_SubscribeClient.Recv()
+	if err == nil {
+		*s.count++
+	}
+	return resp, err
+}
+
+// queryPath returns the path of the query in the RPCInfo, which has no origin, or nil if there is no query.
+func queryPath(t *testing.T, info *ygnmi.RPCInfo) *gpb.Path {
+	t.Helper()
+	if info.Query == nil {
+		return nil
+	}
+	p, _, err := ygnmi.ResolvePath(info.Query.PathStruct())
+	if err != nil {
+		t.Fatalf("ResolvePath() returned unexpected error: %v", err)
+	}
+	return p
+}
+
+func TestInterceptors(t *testing.T) {
+	onePath := testutil.GNMIPath(t, "/parent/child/config/one")
+	config := []*gpb.Update{{
+		Path: onePath,
+		Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "foo"}},
+	}}
+	q := exampleocpath.Root().Parent().Child().One().Config()
+
+	t.Run("set", func(t *testing.T) {
+		var calls []string
+		var gotPaths []*gpb.Path
+		outer := ygnmi.UnaryInterceptor(func(ctx context.Context, info *ygnmi.RPCInfo, req proto.Message, invoker ygnmi.UnaryInvoker) (proto.Message, error) {
+			calls = append(calls, "outer "+info.Method)
+			gotPaths = append(gotPaths, queryPath(t, info))
+			return invoker(ctx, req)
+		})
+		inner := ygnmi.UnaryInterceptor(func(ctx context.Context, info *ygnmi.RPCInfo, req proto.Message, invoker ygnmi.UnaryInvoker) (proto.Message, error) {
+			calls = append(calls, "inner "+info.Method)
+			sr := proto.Clone(req).(*gpb.SetRequest)
+			sr.Prefix.Target = "intercepted"
+			resp, err := invoker(ctx, sr)
+			if err != nil {
+				return nil, err
+			}
+			resp.(*gpb.SetResponse).Timestamp = 200
+			return resp, nil
+		})
+		srv := &configServer{config: config}
+		c := newServerClient(t, srv, ygnmi.WithInterceptors(outer, inner))
+
+		res, err := ygnmi.Replace(context.Background(), c, q, "bar")
+		if err != nil {
+			t.Fatalf("Replace() returned unexpected error: %v", err)
+		}
+		if got := res.RawResponse.GetTimestamp(); got != 200 {
+			t.Errorf("Replace() returned response timestamp %d, want 200", got)
+		}
+		if diff := cmp.Diff([]string{"outer Set", "inner Set"}, calls); diff != "" {
+			t.Errorf("Interceptors called in unexpected order (-want,+got):\n%s", diff)
+		}
+		if diff := cmp.Diff([]*gpb.Path{{Elem: onePath.GetElem()}}, gotPaths, protocmp.Transform()); diff != "" {
+			t.Errorf("Interceptor got unexpected query path (-want,+got):\n%s", diff)
+		}
+		reqs := srv.Requests()
+		if len(reqs) != 1 {
+			t.Fatalf("Number of SetRequests got %d, want 1", len(reqs))
+		}
+		if got := reqs[0].GetPrefix().GetTarget(); got != "intercepted" {
+			t.Errorf("SetRequest target got %q, want %q", got, "intercepted")
+		}
+	})
+
+	t.Run("get", func(t *testing.T) {
+		var gotPaths []*gpb.Path
+		fake := ygnmi.UnaryInterceptor(func(ctx context.Context, info *ygnmi.RPCInfo, req proto.Message, invoker ygnmi.UnaryInvoker) (proto.Message, error) {
+			if info.Method != "Get" {
+				t.Errorf("Interceptor got method %q, want Get", info.Method)
+			}
+			gotPaths = append(gotPaths, queryPath(t, info))
+			return &gpb.GetResponse{Notification: []*gpb.Notification{{
+				Timestamp: 1,
+				Update: []*gpb.Update{{
+					Path: onePath,
+					Val:  &gpb.TypedValue{Value: &gpb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`"intercepted"`)}},
+				}},
+			}}}, nil
+		})
+		c := newServerClient(t, &configServer{config: config}, ygnmi.WithInterceptors(fake))
+
+		got, err := ygnmi.Get(context.Background(), c, q, ygnmi.WithUseGet())
+		if err != nil {
+			t.Fatalf("Get() returned unexpected error: %v", err)
+		}
+		if got != "intercepted" {
+			t.Errorf("Get() returned %q, want %q", got, "intercepted")
+		}
+		if diff := cmp.Diff([]*gpb.Path{{Elem: onePath.GetElem()}}, gotPaths, protocmp.Transform()); diff != "" {
+			t.Errorf("Interceptor got unexpected query path (-want,+got):\n%s", diff)
+		}
+	})
+
+	t.Run("get wrong response", func(t *testing.T) {
+		wrong := ygnmi.UnaryInterceptor(func(context.Context, *ygnmi.RPCInfo, proto.Message, ygnmi.UnaryInvoker) (proto.Message, error) {
+			return &gpb.SetResponse{}, nil
+		})
+		c := newServerClient(t, &configServer{config: config}, ygnmi.WithInterceptors(wrong))
+
+		_, err := ygnmi.Get(context.Background(), c, q, ygnmi.WithUseGet())
+		if diff := errdiff.Substring(err, "interceptor returned a *gnmi.SetResponse response"); diff != "" {
+			t.Errorf("Get() returned unexpected diff: %s", diff)
+		}
+	})
+
+	t.Run("subscribe", func(t *testing.T) {
+		var calls []string
+		var gotPaths []*gpb.Path
+		var count int
+		outer := ygnmi.StreamInterceptor(func(ctx context.Context, info *ygnmi.RPCInfo, req *gpb.SubscribeRequest, streamer ygnmi.Streamer) (gpb.GNMI_SubscribeClient, error) {
+			calls = append(calls, "outer "+info.Method)
+			gotPaths = append(gotPaths, queryPath(t, info))
+			sub, err := streamer(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+			return &countingSubscriber{GNMI_SubscribeClient: sub, count: &count}, nil
+		})
+		inner := ygnmi.StreamInterceptor(func(ctx context.Context, info *ygnmi.RPCInfo, req *gpb.SubscribeRequest, streamer ygnmi.Streamer) (gpb.GNMI_SubscribeClient, error) {
+			calls = append(calls, "inner "+info.Method)
+			return streamer(ctx, req)
+		})
+		c := newServerClient(t, &configServer{config: config}, ygnmi.WithInterceptors(outer, inner))
+
+		got, err := ygnmi.Get(context.Background(), c, q)
+		if err != nil {
+			t.Fatalf("Get() returned unexpected error: %v", err)
+		}
+		if got != "foo" {
+			t.Errorf("Get() returned %q, want %q", got, "foo")
+		}
+		if diff := cmp.Diff([]string{"outer Subscribe", "inner Subscribe"}, calls); diff != "" {
+			t.Errorf("Interceptors called in unexpected order (-want,+got):\n%s", diff)
+		}
+		if diff := cmp.Diff([]*gpb.Path{{Elem: onePath.GetElem()}}, gotPaths, protocmp.Transform()); diff != "" {
+			t.Errorf("Interceptor got unexpected query path (-want,+got):\n%s", diff)
+		}
+		// The update and the sync response.
+		if count != 2 {
+			t.Errorf("Interceptor counted %d responses, want 2", count)
+		}
+	})
+
+	t.Run("logged requests", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug - 4}))
+		unary := ygnmi.UnaryInterceptor(func(ctx context.Context, info *ygnmi.RPCInfo, req proto.Message, invoker ygnmi.UnaryInvoker) (proto.Message, error) {
+			gr := proto.Clone(req).(*gpb.GetRequest)
+			gr.Prefix.Target = "intercepted-get"
+			return invoker(ctx, gr)
+		})
+		stream := ygnmi.StreamInterceptor(func(ctx context.Context, info *ygnmi.RPCInfo, req *gpb.SubscribeRequest, streamer ygnmi.Streamer) (gpb.GNMI_SubscribeClient, error) {
+			sr := proto.Clone(req).(*gpb.SubscribeRequest)
+			sr.GetSubscribe().Prefix.Target = "intercepted-subscribe"
+			return streamer(ctx, sr)
+		})
+		c := newServerClient(t, &configServer{config: config}, ygnmi.WithInterceptors(unary, stream), ygnmi.WithLogger(logger))
+
+		if _, err := ygnmi.Get(context.Background(), c, q); err != nil {
+			t.Fatalf("Get() returned unexpected error: %v", err)
+		}
+		// The server doesn't implement Get, only the logged request matters.
+		ygnmi.Get(context.Background(), c, q, ygnmi.WithUseGet())
+
+		logs := buf.String()
+		for _, want := range []string{"intercepted-subscribe", "intercepted-get"} {
+			if !strings.Contains(logs, want) {
+				t.Errorf("Logs don't contain the request modified by the interceptor with target %q:\n%s", want, logs)
+			}
+		}
+	})
+}
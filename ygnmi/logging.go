@@ -0,0 +1,195 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/openconfig/ygnmi/internal/logutil"
+	"github.com/openconfig/ygot/util"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+
+	log "github.com/golang/glog"
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// WithLogger emits the logs of the client as structured records to l instead of glog.
+// Records are single-line: gNMI requests and responses are logged with their compact prototext
+// as the "message" attribute, and their paths as the "paths" attribute.
+// Records also have the "target", "operation" and "path" attributes when they apply.
+// glog verbosity levels are mapped to slog levels: V(0) is Info, V(1) is Debug and V(2) is Debug-4,
+// so request dumps are logged at the level mapped from WithRequestLogLevel.
+func WithLogger(l *slog.Logger) ClientOption {
+	return func(c *Client) error {
+		c.log.slog = l
+		return nil
+	}
+}
+
+// logger emits the logs of a client, to glog unless WithLogger is used.
+// A nil logger logs to glog, without the client attributes.
+type logger struct {
+	client *Client
+	slog   *slog.Logger
+}
+
+// slogLevel returns the slog level of a glog verbosity level.
+func slogLevel(v log.Level) slog.Level {
+	return slog.LevelInfo - slog.Level(4*v)
+}
+
+// attrs returns the attributes of the client followed by args.
+func (l *logger) attrs(args []any) []any {
+	return append([]any{"target", l.client.target}, args...)
+}
+
+// enabled returns whether messages at the glog verbosity level v are logged.
+// Callers on hot paths check it before building costly arguments of info.
+func (l *logger) enabled(ctx context.Context, v log.Level) bool {
+	if l == nil || l.slog == nil {
+		return bool(log.V(v))
+	}
+	return l.slog.Enabled(ctx, slogLevel(v))
+}
+
+// redactor returns the redactor of the sensitive values in the logs, see WithRedactionRules,
+// or nil if the client has no redaction rules.
+func (l *logger) redactor() *redactor {
+	if l == nil || l.client == nil {
+		return nil
+	}
+	return l.client.redaction
+}
+
+// info logs the message with the key-value pairs in args at the glog verbosity level v.
+func (l *logger) info(ctx context.Context, v log.Level, msg string, args ...any) {
+	if !l.enabled(ctx, v) {
+		return
+	}
+	if l == nil || l.slog == nil {
+		log.InfoContextDepth(ctx, 1, glogMessage(msg, args))
+		return
+	}
+	l.slog.Log(ctx, slogLevel(v), msg, l.attrs(args)...)
+}
+
+// warning logs the warning with the key-value pairs in args.
+func (l *logger) warning(ctx context.Context, msg string, args ...any) {
+	if l == nil || l.slog == nil {
+		log.WarningContextDepth(ctx, 1, glogMessage(msg, args))
+		return
+	}
+	l.slog.WarnContext(ctx, msg, l.attrs(args)...)
+}
+
+// error logs the error with the key-value pairs in args.
+func (l *logger) error(ctx context.Context, msg string, args ...any) {
+	if l == nil || l.slog == nil {
+		log.ErrorContextDepth(ctx, 1, glogMessage(msg, args))
+		return
+	}
+	l.slog.ErrorContext(ctx, msg, l.attrs(args)...)
+}
+
+// request logs a gNMI request or response of the operation at the request log level of the client.
+func (l *logger) request(ctx context.Context, op string, m proto.Message) {
+	if m == nil {
+		return
+	}
+	if !l.enabled(ctx, l.client.requestLogLevel) {
+		return
+	}
+	m = l.redactor().message(m)
+	if l.slog == nil {
+		switch m := m.(type) {
+		case *gpb.SetRequest:
+			logutil.LogByLine(l.client.requestLogLevel, prettySetRequest(m))
+		case *gpb.SetResponse:
+			log.InfoContextf(ctx, "SetResponse:\n%s", prototext.Format(m))
+		default:
+			log.InfoContext(ctx, prototext.Format(m))
+		}
+		return
+	}
+	l.slog.Log(ctx, slogLevel(l.client.requestLogLevel), string(m.ProtoReflect().Descriptor().Name()), l.attrs([]any{
+		"operation", op,
+		"paths", messagePaths(m),
+		"message", compactText(m),
+	})...)
+}
+
+// glogMessage formats the message with the key-value pairs in args for glog.
+func glogMessage(msg string, args []any) string {
+	var b strings.Builder
+	b.WriteString(msg)
+	for i := 0; i+1 < len(args); i += 2 {
+		fmt.Fprintf(&b, " %v=%v", args[i], args[i+1])
+	}
+	return b.String()
+}
+
+// compactText returns the single-line prototext of the message.
+func compactText(m proto.Message) string {
+	return prototext.MarshalOptions{}.Format(m)
+}
+
+// messagePaths returns the paths of a gNMI request or response, joined with its prefix.
+func messagePaths(m proto.Message) []string {
+	var prefix *gpb.Path
+	var paths []*gpb.Path
+	switch m := m.(type) {
+	case *gpb.SubscribeRequest:
+		prefix = m.GetSubscribe().GetPrefix()
+		for _, s := range m.GetSubscribe().GetSubscription() {
+			paths = append(paths, s.GetPath())
+		}
+	case *gpb.GetRequest:
+		prefix, paths = m.GetPrefix(), m.GetPath()
+	case *gpb.SetRequest:
+		prefix = m.GetPrefix()
+		paths = append(paths, m.GetDelete()...)
+		for _, updates := range [][]*gpb.Update{m.GetReplace(), m.GetUpdate(), m.GetUnionReplace()} {
+			for _, u := range updates {
+				paths = append(paths, u.GetPath())
+			}
+		}
+	case *gpb.SetResponse:
+		prefix = m.GetPrefix()
+		for _, r := range m.GetResponse() {
+			paths = append(paths, r.GetPath())
+		}
+	}
+	var strs []string
+	for _, p := range paths {
+		if j, err := util.JoinPaths(prefix, p); err == nil {
+			p = j
+		}
+		strs = append(strs, pathString(p))
+	}
+	return strs
+}
+
+// pathStrings returns the string representations of the paths.
+func pathStrings(paths []*gpb.Path) []string {
+	var strs []string
+	for _, p := range paths {
+		strs = append(strs, pathString(p))
+	}
+	return strs
+}
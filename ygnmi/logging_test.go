@@ -0,0 +1,112 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/ygnmi/exampleoc"
+	"github.com/openconfig/ygnmi/exampleoc/exampleocpath"
+	"github.com/openconfig/ygnmi/internal/testutil"
+	"github.com/openconfig/ygnmi/ygnmi"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func TestWithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug - 4}))
+	c := newServerClient(t, &configServer{config: []*gpb.Update{{
+		Path: testutil.GNMIPath(t, "/parent/child/config/one"),
+		Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "foo"}},
+	}}}, ygnmi.WithTarget("dut"), ygnmi.WithLogger(logger))
+	ctx := context.Background()
+	child := exampleocpath.Root().Parent().Child()
+
+	if _, err := ygnmi.Replace(ctx, c, child.Three().Config(), exampleoc.Child_Three_ONE); err != nil {
+		t.Fatalf("Replace() returned unexpected error: %v", err)
+	}
+	if _, err := ygnmi.Lookup(ctx, c, child.One().Config()); err != nil {
+		t.Fatalf("Lookup() returned unexpected error: %v", err)
+	}
+	r, err := ygnmi.NewReconciler(c, child.Config())
+	if err != nil {
+		t.Fatalf("NewReconciler() returned unexpected error: %v", err)
+	}
+	r.Start(ctx, func(*ygnmi.Value[*exampleoc.Parent_Child], *ygnmi.Value[*exampleoc.Parent_Child]) error {
+		return errors.New("not reconciled")
+	})
+	if err := r.Await(); !errors.Is(err, io.EOF) {
+		t.Fatalf("Await() returned unexpected error: %v", err)
+	}
+
+	// records contains the records by message, with the attributes compared by the test.
+	records := map[string]map[string]any{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Logged record isn't a single line of JSON: %q: %v", line, err)
+		}
+		msg := record["msg"].(string)
+		if _, ok := records[msg]; ok {
+			continue
+		}
+		records[msg] = map[string]any{}
+		for _, k := range []string{"level", "target", "operation", "path", "paths"} {
+			if v, ok := record[k]; ok {
+				records[msg][k] = v
+			}
+		}
+	}
+
+	want := map[string]map[string]any{
+		"SetRequest": {
+			"level":     "DEBUG",
+			"target":    "dut",
+			"operation": "Set",
+			"paths":     []any{"/parent/child/config/three"},
+		},
+		"SubscribeRequest": {
+			"level":     "DEBUG",
+			"target":    "dut",
+			"operation": "Subscribe",
+			"paths":     []any{"/parent/child/config/one"},
+		},
+		"Received gNMI Update": {
+			"level":  "DEBUG-4",
+			"target": "dut",
+			"path":   "/parent/child/config/one",
+		},
+		"reconciler error": {
+			"level":     "WARN",
+			"target":    "dut",
+			"operation": "Reconciler",
+			"path":      "/parent/child",
+		},
+	}
+	for msg, want := range want {
+		if diff := cmp.Diff(want, records[msg]); diff != "" {
+			t.Errorf("Record %q has unexpected attributes (-want,+got):\n%s", msg, diff)
+		}
+	}
+}
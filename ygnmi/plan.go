@@ -0,0 +1,229 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/openconfig/gnmi/value"
+	"github.com/openconfig/ygot/util"
+	"github.com/openconfig/ygot/ygot"
+	"google.golang.org/protobuf/encoding/prototext"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// ConfigChange is a change to a leaf of the config made by a planned SetBatch.
+type ConfigChange struct {
+	// Path is the path of the leaf.
+	Path *gpb.Path
+	// Old is the current value of the leaf, or nil if the leaf is added.
+	Old *gpb.TypedValue
+	// New is the desired value of the leaf, or nil if the leaf is deleted.
+	New *gpb.TypedValue
+}
+
+func (c *ConfigChange) String() string {
+	path := pathString(c.Path)
+	switch {
+	case c.New == nil:
+		return fmt.Sprintf("- %s: %s", path, formatTypedValue(c.Old))
+	case c.Old == nil:
+		return fmt.Sprintf("+ %s: %s", path, formatTypedValue(c.New))
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", path, formatTypedValue(c.Old), formatTypedValue(c.New))
+	}
+}
+
+// ConfigPlan describes the changes made to the config by the SetBatch returned by Plan.
+type ConfigPlan struct {
+	// Changes are the changed leaves, sorted by path.
+	Changes []*ConfigChange
+}
+
+// String returns the plan with one change per line, prefixed by "+" for
+// added leaves, "~" for updated leaves and "-" for deleted leaves.
+func (p *ConfigPlan) String() string {
+	if len(p.Changes) == 0 {
+		return "no changes"
+	}
+	lines := make([]string, 0, len(p.Changes))
+	for _, c := range p.Changes {
+		lines = append(lines, c.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Plan fetches the current config at the query, and returns a SetBatch containing the minimal
+// updates and deletes that make it equal to desired, along with a description of the changes.
+// Like Replace, leaves that are present on the device but not in desired are deleted.
+// For non-leaf queries, the difference is computed with ygot.Diff, and each changed leaf is a separate operation,
+// except for removed list entries, which are deleted with a single operation on the entry path.
+// The batch is empty if the config is already equal to desired.
+// Other operations may be added to the batch before calling Set, and the batch supports SetWithRollback,
+// which restores the whole config at the query.
+func Plan[T any](ctx context.Context, c *Client, q ConfigQuery[T], desired T, opts ...Option) (*SetBatch, *ConfigPlan, error) {
+	current, err := Lookup[T](ctx, c, q, opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to lookup current config: %w", err)
+	}
+	queryPath, err := resolvePath(q.PathStruct())
+	if err != nil {
+		return nil, nil, err
+	}
+	sb := &SetBatch{}
+	plan := &ConfigPlan{}
+	if q.isLeaf() {
+		cur, ok := current.Val()
+		if ok && reflect.DeepEqual(cur, desired) {
+			return sb, plan, nil
+		}
+		change := &ConfigChange{Path: queryPath}
+		if change.New, err = ygot.EncodeTypedValue(desired, gpb.Encoding_JSON_IETF); err != nil {
+			return nil, nil, fmt.Errorf("failed to encode desired value: %w", err)
+		}
+		if ok {
+			if change.Old, err = ygot.EncodeTypedValue(cur, gpb.Encoding_JSON_IETF); err != nil {
+				return nil, nil, fmt.Errorf("failed to encode current value: %w", err)
+			}
+		}
+		BatchReplace(sb, q, desired)
+		plan.Changes = append(plan.Changes, change)
+		return sb, plan, nil
+	}
+
+	curGS, desGS := ygot.GoStruct(q.goStruct()), ygot.GoStruct(q.goStruct())
+	if gs, ok := any(current.val).(ygot.GoStruct); current.IsPresent() && ok {
+		curGS = gs
+	}
+	if gs, ok := any(desired).(ygot.GoStruct); ok && !reflect.ValueOf(gs).IsNil() {
+		desGS = gs
+	}
+	changes, err := diffGoStructs(curGS, desGS, q.isShadowPath())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to diff current and desired config: %w", err)
+	}
+	// The updates of the diff from an empty GoStruct are the leaves of desired.
+	desLeaves, err := ygot.Diff(q.goStruct(), desGS, &ygot.DiffPathOpt{MapToSinglePath: true, PreferShadowPath: q.isShadowPath()})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list desired config: %w", err)
+	}
+	deletedEntries := map[string]bool{}
+	for _, change := range changes {
+		path, err := util.JoinPaths(queryPath, change.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+		switch entry := removedEntry(change.Path, desLeaves.GetUpdate()); {
+		case change.New != nil:
+			sb.ops = append(sb.ops, &batchOp{rawPath: path, val: change.New, mode: updatePath, isLeaf: true})
+		case entry != nil:
+			// Every leaf of the entry is deleted, so the entry is deleted with a single operation.
+			entryPath, err := util.JoinPaths(queryPath, entry)
+			if err != nil {
+				return nil, nil, err
+			}
+			if s := pathString(entryPath); !deletedEntries[s] {
+				deletedEntries[s] = true
+				sb.ops = append(sb.ops, &batchOp{rawPath: entryPath, mode: deletePath})
+			}
+		default:
+			sb.ops = append(sb.ops, &batchOp{rawPath: path, mode: deletePath})
+		}
+		change.Path = path
+		plan.Changes = append(plan.Changes, change)
+	}
+	// The first operation snapshots the whole config at the query, the others don't need a snapshot.
+	for i, op := range sb.ops {
+		if i == 0 {
+			op.snapshot = snapshotFn(q)
+		} else {
+			op.snapshot = func(context.Context, *Client, *SetBatch, []Option) error { return nil }
+		}
+	}
+	return sb, plan, nil
+}
+
+// removedEntry returns the outermost list entry in the path that contains none of the desired leaves,
+// or nil if there is none. The path and the leaves are relative to the same GoStruct.
+func removedEntry(path *gpb.Path, desired []*gpb.Update) *gpb.Path {
+	for i, e := range path.GetElem() {
+		if len(e.GetKey()) == 0 {
+			continue
+		}
+		entry := &gpb.Path{Elem: path.GetElem()[:i+1]}
+		if !slices.ContainsFunc(desired, func(u *gpb.Update) bool { return util.PathMatchesPathElemPrefix(u.GetPath(), entry) }) {
+			return entry
+		}
+	}
+	return nil
+}
+
+// diffGoStructs returns the leaves that differ between two GoStructs of the same type, sorted by path.
+// The paths are relative to the GoStructs, Old is the value in from and New the value in to.
+func diffGoStructs(from, to ygot.GoStruct, preferShadowPath bool) ([]*ConfigChange, error) {
+	diffOpt := &ygot.DiffPathOpt{MapToSinglePath: true, PreferShadowPath: preferShadowPath}
+	forward, err := ygot.Diff(from, to, diffOpt)
+	if err != nil {
+		return nil, err
+	}
+	// The updates of the reverse diff contain the values in from of the changed leaves.
+	reverse, err := ygot.Diff(to, from, diffOpt)
+	if err != nil {
+		return nil, err
+	}
+	oldVals := map[string]*gpb.TypedValue{}
+	for _, u := range reverse.GetUpdate() {
+		oldVals[pathString(u.GetPath())] = u.GetVal()
+	}
+	var changes []*ConfigChange
+	for _, p := range forward.GetDelete() {
+		changes = append(changes, &ConfigChange{Path: p, Old: oldVals[pathString(p)]})
+	}
+	for _, u := range forward.GetUpdate() {
+		changes = append(changes, &ConfigChange{Path: u.GetPath(), Old: oldVals[pathString(u.GetPath())], New: u.GetVal()})
+	}
+	// The order of the diff is unspecified, sort it for a stable result.
+	sort.Slice(changes, func(i, j int) bool {
+		return pathString(changes[i].Path) < pathString(changes[j].Path)
+	})
+	return changes, nil
+}
+
+// pathString returns a human-readable representation of a path.
+func pathString(p *gpb.Path) string {
+	s, err := ygot.PathToString(p)
+	if err != nil {
+		return prototext.Format(p)
+	}
+	return s
+}
+
+// formatTypedValue returns a human-readable representation of a scalar TypedValue.
+func formatTypedValue(tv *gpb.TypedValue) string {
+	v, err := value.ToScalar(tv)
+	if err != nil {
+		return prototext.Format(tv)
+	}
+	if s, ok := v.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,168 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/ygnmi/exampleoc"
+	"github.com/openconfig/ygnmi/exampleoc/exampleocpath"
+	"github.com/openconfig/ygnmi/internal/testutil"
+	"github.com/openconfig/ygnmi/ygnmi"
+	"github.com/openconfig/ygot/ygot"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func TestPlan(t *testing.T) {
+	onePath := testutil.GNMIPath(t, "/parent/child/config/one")
+	threePath := testutil.GNMIPath(t, "/parent/child/config/three")
+	fourPath := testutil.GNMIPath(t, "/parent/child/config/four")
+	config := []*gpb.Update{{
+		Path: onePath,
+		Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "old"}},
+	}, {
+		Path: threePath,
+		Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "ONE"}},
+	}}
+
+	fooKeyPath := testutil.GNMIPath(t, "/model/a/single-key[key=foo]/config/key")
+	fooValuePath := testutil.GNMIPath(t, "/model/a/single-key[key=foo]/config/value")
+	barKeyPath := testutil.GNMIPath(t, "/model/a/single-key[key=bar]/config/key")
+	barValuePath := testutil.GNMIPath(t, "/model/a/single-key[key=bar]/config/value")
+	listConfig := []*gpb.Update{{
+		Path: fooKeyPath,
+		Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "foo"}},
+	}, {
+		Path: fooValuePath,
+		Val:  &gpb.TypedValue{Value: &gpb.TypedValue_IntVal{IntVal: 1}},
+	}, {
+		Path: barKeyPath,
+		Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "bar"}},
+	}, {
+		Path: barValuePath,
+		Val:  &gpb.TypedValue{Value: &gpb.TypedValue_IntVal{IntVal: 2}},
+	}}
+
+	tests := []struct {
+		desc string
+		// config is the current config, the config of the child container if nil.
+		config      []*gpb.Update
+		plan        func(*ygnmi.Client) (*ygnmi.SetBatch, *ygnmi.ConfigPlan, error)
+		wantPlan    string
+		wantRequest *gpb.SetRequest
+	}{{
+		desc: "container",
+		plan: func(c *ygnmi.Client) (*ygnmi.SetBatch, *ygnmi.ConfigPlan, error) {
+			return ygnmi.Plan(context.Background(), c, exampleocpath.Root().Parent().Child().Config(), &exampleoc.Parent_Child{
+				One:  ygot.String("new"),
+				Four: exampleoc.Binary("abc"),
+			})
+		},
+		wantPlan: "+ /parent/child/config/four: [97 98 99]\n" +
+			"~ /parent/child/config/one: \"old\" -> \"new\"\n" +
+			"- /parent/child/config/three: \"ONE\"",
+		wantRequest: &gpb.SetRequest{
+			Prefix: &gpb.Path{},
+			Delete: []*gpb.Path{threePath},
+			Update: []*gpb.Update{{
+				Path: fourPath,
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_BytesVal{BytesVal: []byte("abc")}},
+			}, {
+				Path: onePath,
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "new"}},
+			}},
+		},
+	}, {
+		desc: "container unchanged",
+		plan: func(c *ygnmi.Client) (*ygnmi.SetBatch, *ygnmi.ConfigPlan, error) {
+			return ygnmi.Plan(context.Background(), c, exampleocpath.Root().Parent().Child().Config(), &exampleoc.Parent_Child{
+				One:   ygot.String("old"),
+				Three: exampleoc.Child_Three_ONE,
+			})
+		},
+		wantPlan: "no changes",
+	}, {
+		desc: "leaf",
+		plan: func(c *ygnmi.Client) (*ygnmi.SetBatch, *ygnmi.ConfigPlan, error) {
+			return ygnmi.Plan(context.Background(), c, exampleocpath.Root().Parent().Child().One().Config(), "new")
+		},
+		wantPlan: "~ /parent/child/config/one: \"old\" -> \"new\"",
+		wantRequest: &gpb.SetRequest{
+			Prefix: &gpb.Path{},
+			Replace: []*gpb.Update{{
+				Path: onePath,
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`"new"`)}},
+			}},
+		},
+	}, {
+		desc: "leaf unchanged",
+		plan: func(c *ygnmi.Client) (*ygnmi.SetBatch, *ygnmi.ConfigPlan, error) {
+			return ygnmi.Plan(context.Background(), c, exampleocpath.Root().Parent().Child().One().Config(), "old")
+		},
+		wantPlan: "no changes",
+	}, {
+		desc:   "remove list entry",
+		config: listConfig,
+		plan: func(c *ygnmi.Client) (*ygnmi.SetBatch, *ygnmi.ConfigPlan, error) {
+			m := &exampleoc.Model{}
+			m.GetOrCreateSingleKey("bar").Value = ygot.Int64(3)
+			return ygnmi.Plan(context.Background(), c, exampleocpath.Root().Model().Config(), m)
+		},
+		wantPlan: "~ /model/a/single-key[key=bar]/config/value: 2 -> 3\n" +
+			"- /model/a/single-key[key=foo]/config/value: 1\n" +
+			"- /model/a/single-key[key=foo]/key: \"foo\"",
+		wantRequest: &gpb.SetRequest{
+			Prefix: &gpb.Path{},
+			Delete: []*gpb.Path{testutil.GNMIPath(t, "/model/a/single-key[key=foo]")},
+			Update: []*gpb.Update{{
+				Path: barValuePath,
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_IntVal{IntVal: 3}},
+			}},
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			srv := &configServer{config: config}
+			if tt.config != nil {
+				srv.config = tt.config
+			}
+			c := newServerClient(t, srv)
+			sb, plan, err := tt.plan(c)
+			if err != nil {
+				t.Fatalf("Plan() returned unexpected error: %v", err)
+			}
+			if got := plan.String(); got != tt.wantPlan {
+				t.Errorf("Plan() got plan:\n%s\nwant:\n%s", got, tt.wantPlan)
+			}
+			if tt.wantRequest == nil {
+				return
+			}
+			if _, err := sb.Set(context.Background(), c); err != nil {
+				t.Fatalf("Set() returned unexpected error: %v", err)
+			}
+			reqs := srv.Requests()
+			if len(reqs) != 1 {
+				t.Fatalf("Number of SetRequests got %d, want 1", len(reqs))
+			}
+			if diff := cmp.Diff(tt.wantRequest, reqs[0], protocmp.Transform()); diff != "" {
+				t.Errorf("Set() sent unexpected SetRequest (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
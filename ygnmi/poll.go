@@ -0,0 +1,194 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// Poller is a POLL subscription of a SingletonQuery.
+type Poller[T any] struct {
+	ps *pollStream
+	q  SingletonQuery[T]
+	o  *opt
+}
+
+// Poll creates a POLL subscription for the query. The values are fetched by calling Poll on the returned Poller.
+// The subscription is kept open until the context is cancelled or Close is called.
+func Poll[T any](ctx context.Context, c *Client, q SingletonQuery[T], opts ...Option) (*Poller[T], error) {
+	resolvedOpts := resolveClientOpts(c, opts)
+	ps, err := newPollStream[T](ctx, c, q, resolvedOpts)
+	if err != nil {
+		return nil, err
+	}
+	return &Poller[T]{ps: ps, q: q, o: resolvedOpts}, nil
+}
+
+// Poll sends a poll trigger on the subscription, waits for the sync response
+// and returns the received value.
+func (p *Poller[T]) Poll(ctx context.Context) (*Value[T], error) {
+	data, err := p.ps.poll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalValue(data, p.q, p.o)
+}
+
+// Close closes the subscription.
+func (p *Poller[T]) Close() {
+	p.ps.cancel()
+}
+
+// WildcardPoller is a POLL subscription of a WildcardQuery.
+type WildcardPoller[T any] struct {
+	ps *pollStream
+	q  WildcardQuery[T]
+	o  *opt
+}
+
+// PollAll creates a POLL subscription for the query. The values are fetched by calling Poll on the returned WildcardPoller.
+// The subscription is kept open until the context is cancelled or Close is called.
+func PollAll[T any](ctx context.Context, c *Client, q WildcardQuery[T], opts ...Option) (*WildcardPoller[T], error) {
+	resolvedOpts := resolveClientOpts(c, opts)
+	ps, err := newPollStream[T](ctx, c, q, resolvedOpts)
+	if err != nil {
+		return nil, err
+	}
+	return &WildcardPoller[T]{ps: ps, q: q, o: resolvedOpts}, nil
+}
+
+// Poll sends a poll trigger on the subscription, waits for the sync response
+// and returns the received values. It returns an empty list if no values are present.
+func (p *WildcardPoller[T]) Poll(ctx context.Context) ([]*Value[T], error) {
+	data, err := p.ps.poll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalValues(data, p.q, p.o)
+}
+
+// Close closes the subscription.
+func (p *WildcardPoller[T]) Close() {
+	p.ps.cancel()
+}
+
+// pollStream sends poll triggers on a POLL subscription and receives the responses to them.
+type pollStream struct {
+	sub    gpb.GNMI_SubscribeClient
+	cancel context.CancelFunc
+	log    *logger
+	// respCh receives each response, it is closed when the subscription fails with err.
+	respCh chan pollResponse
+	err    error
+
+	mu sync.Mutex
+	// pending is the number of sync responses expected before the response to the next poll.
+	pending int
+}
+
+// pollResponse contains the datapoints of a single response.
+type pollResponse struct {
+	data []*DataPoint
+	sync bool
+}
+
+// newPollStream creates a POLL subscription for the query.
+func newPollStream[T any](ctx context.Context, c *Client, q AnyQuery[T], o *opt) (*pollStream, error) {
+	queryPath, err := resolvePath(q.PathStruct())
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve path: %w", err)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	sub, err := subscribe[T](ctx, c, q, gpb.SubscriptionList_POLL, o)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to subscribe to path: %w", err)
+	}
+	ps := &pollStream{
+		sub:    sub,
+		cancel: cancel,
+		log:    c.log,
+		respCh: make(chan pollResponse),
+		// Like for the other modes, the target sends the current values followed by a sync response
+		// when the subscription is created. These are discarded by the first poll.
+		pending: 1,
+	}
+	go ps.run(ctx, queryPath, o)
+	return ps, nil
+}
+
+// run receives the responses on the subscription until it fails.
+func (ps *pollStream) run(ctx context.Context, queryPath *gpb.Path, o *opt) {
+	defer close(ps.respCh)
+	for {
+		data, synced, err := receive(ctx, ps.sub, nil, false, queryPath, o)
+		if err != nil {
+			ps.err = err
+			return
+		}
+		select {
+		case ps.respCh <- pollResponse{data: data, sync: synced}:
+		case <-ctx.Done():
+			ps.err = ctx.Err()
+			return
+		}
+	}
+}
+
+// poll sends a poll trigger and returns the datapoints received until the corresponding sync response.
+func (ps *pollStream) poll(ctx context.Context) ([]*DataPoint, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.log.info(ctx, 2, "Sending gNMI Poll trigger", "operation", "Subscribe")
+	if err := ps.sub.Send(&gpb.SubscribeRequest{Request: &gpb.SubscribeRequest_Poll{Poll: &gpb.Poll{}}}); err != nil {
+		// If the stream is closed, the real error is only visible on Recv.
+		if errors.Is(err, io.EOF) {
+			for range ps.respCh {
+			}
+			if ps.err != nil {
+				err = ps.err
+			}
+		}
+		return nil, fmt.Errorf("gNMI failed to send poll trigger: %w", err)
+	}
+	ps.pending++
+	var data []*DataPoint
+	for {
+		select {
+		case resp, ok := <-ps.respCh:
+			if !ok {
+				return nil, fmt.Errorf("error receiving gNMI response: %w", ps.err)
+			}
+			data = append(data, resp.data...)
+			if resp.sync {
+				ps.pending--
+				if ps.pending == 0 {
+					return data, nil
+				}
+				// The response belongs to an earlier poll, so discard it.
+				data = nil
+			}
+		case <-ctx.Done():
+			// The response to this poll is discarded by the next one.
+			return nil, ctx.Err()
+		}
+	}
+}
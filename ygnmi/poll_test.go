@@ -0,0 +1,145 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/ygnmi/exampleoc/exampleocpath"
+	"github.com/openconfig/ygnmi/internal/gnmitestutil"
+	"github.com/openconfig/ygnmi/internal/testutil"
+	"github.com/openconfig/ygnmi/ygnmi"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// verifyPollRequestsSent verifies that a POLL subscription was created followed by wantPolls poll triggers.
+func verifyPollRequestsSent(t *testing.T, fakeGNMI *gnmitestutil.FakeGNMI, wantPolls int) {
+	t.Helper()
+	requests := fakeGNMI.Requests()
+	if len(requests) == 0 {
+		t.Fatalf("No subscription requests sent")
+	}
+	if got := requests[0].GetSubscribe().GetMode(); got != gpb.SubscriptionList_POLL {
+		t.Errorf("Subscription mode got %v, want %v", got, gpb.SubscriptionList_POLL)
+	}
+	var gotPolls int
+	for _, req := range requests[1:] {
+		if req.GetPoll() != nil {
+			gotPolls++
+		}
+	}
+	if gotPolls != wantPolls {
+		t.Errorf("Number of poll triggers sent got %d, want %d", gotPolls, wantPolls)
+	}
+}
+
+func TestPoll(t *testing.T) {
+	fakeGNMI, c := newClient(t)
+	leafPath := testutil.GNMIPath(t, "/remote-container/state/a-leaf")
+	lq := exampleocpath.Root().RemoteContainer().ALeaf().State()
+
+	fakeGNMI.Stub().Notification(&gpb.Notification{
+		Timestamp: 100,
+		Update: []*gpb.Update{{
+			Path: leafPath,
+			Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "foo"}},
+		}},
+	}).Sync()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	p, err := ygnmi.Poll(ctx, c, lq)
+	if err != nil {
+		t.Fatalf("Poll() returned unexpected error: %v", err)
+	}
+	defer p.Close()
+	for i := 0; i < 2; i++ {
+		got, err := p.Poll(ctx)
+		if err != nil {
+			t.Fatalf("Poller.Poll() returned unexpected error: %v", err)
+		}
+		want := (&ygnmi.Value[string]{
+			Path:      leafPath,
+			Timestamp: time.Unix(0, 100),
+		}).SetVal("foo")
+		checkJustReceived(t, got.RecvTimestamp)
+		want.RecvTimestamp = got.RecvTimestamp
+		if diff := cmp.Diff(want, got, cmp.AllowUnexported(ygnmi.Value[string]{}), protocmp.Transform()); diff != "" {
+			t.Errorf("Poller.Poll() returned unexpected diff (-want,+got):\n %s\nComplianceErrors:\n%v", diff, got.ComplianceErrors)
+		}
+	}
+	verifyPollRequestsSent(t, fakeGNMI, 2)
+
+	t.Run("closed", func(t *testing.T) {
+		p.Close()
+		_, err := p.Poll(ctx)
+		if d := errdiff.Substring(err, "context canceled"); d != "" {
+			t.Errorf("Poller.Poll() after Close returned unexpected error: %s", d)
+		}
+	})
+	t.Run("use get", func(t *testing.T) {
+		_, err := ygnmi.Poll(ctx, c, lq, ygnmi.WithUseGet())
+		if d := errdiff.Substring(err, "only valid for ONCE subscriptions"); d != "" {
+			t.Errorf("Poll() returned unexpected error: %s", d)
+		}
+	})
+}
+
+func TestPollAll(t *testing.T) {
+	fakeGNMI, c := newClient(t)
+	key10Path := testutil.GNMIPath(t, "/model/a/single-key[key=10]/state/value")
+	key11Path := testutil.GNMIPath(t, "/model/a/single-key[key=11]/state/value")
+
+	fakeGNMI.Stub().Notification(&gpb.Notification{
+		Timestamp: 100,
+		Update: []*gpb.Update{{
+			Path: key10Path,
+			Val:  &gpb.TypedValue{Value: &gpb.TypedValue_IntVal{IntVal: 100}},
+		}, {
+			Path: key11Path,
+			Val:  &gpb.TypedValue{Value: &gpb.TypedValue_IntVal{IntVal: 101}},
+		}},
+	}).Sync()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	p, err := ygnmi.PollAll(ctx, c, exampleocpath.Root().Model().SingleKeyAny().Value().State())
+	if err != nil {
+		t.Fatalf("PollAll() returned unexpected error: %v", err)
+	}
+	defer p.Close()
+	got, err := p.Poll(ctx)
+	if err != nil {
+		t.Fatalf("WildcardPoller.Poll() returned unexpected error: %v", err)
+	}
+	var gotVals []int64
+	for _, v := range got {
+		val, ok := v.Val()
+		if !ok {
+			t.Errorf("WildcardPoller.Poll() returned non-present value at %v", v.Path)
+		}
+		gotVals = append(gotVals, val)
+	}
+	if diff := cmp.Diff([]int64{100, 101}, gotVals); diff != "" {
+		t.Errorf("WildcardPoller.Poll() returned unexpected diff (-want,+got):\n%s", diff)
+	}
+	verifyPollRequestsSent(t, fakeGNMI, 1)
+}
@@ -0,0 +1,363 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/ygot"
+	"github.com/openconfig/ygot/ytypes"
+	"google.golang.org/protobuf/proto"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// redactedText replaces the values of the sensitive leaves.
+const redactedText = "(redacted)"
+
+// RedactionRule selects sensitive leaves, whose values are masked, see WithRedactionRules.
+// Exactly one of Path and YANGType must be set.
+type RedactionRule struct {
+	// Path is a glob matched against the schema path of the leaves, without keys or origin,
+	// for example "/system/aaa/authentication/users/user/config/password" or "/snmp/communities/*/config/*".
+	// The syntax is the one of path.Match, so "*" doesn't match across elements.
+	// A rule matching a container or a list masks all the leaves in it.
+	Path string
+	// YANGType is the name of a typedef, for example "oc-types:routing-password".
+	// The module prefix is optional and ignored, as the schemas of generated GoStructs don't record the modules of typedefs.
+	// Leaves whose type, or the type of one of whose union members, is the typedef are masked.
+	YANGType string
+}
+
+// redactor masks the values of the leaves matching its path globs.
+type redactor struct {
+	globs []string
+}
+
+// WithRedactionRules sets the rules selecting the sensitive leaves whose values are masked in the logs of the client.
+// The rules also apply to the String methods of the DataPoints, Values, TelemetryErrors, NonConvergedLeafs
+// and DriftEvents produced by the client. Values created by callers aren't masked.
+// Values in JSON encoded TypedValues are masked by matching the paths of their members.
+// YANGType rules are resolved to paths using the schema, which may be nil if there are only Path rules.
+func WithRedactionRules(schema *ytypes.Schema, rules ...RedactionRule) ClientOption {
+	return func(c *Client) error {
+		r, err := newRedactor(schema, rules)
+		if err != nil {
+			return err
+		}
+		c.redaction = r
+		return nil
+	}
+}
+
+// newRedactor returns the redactor of the rules, or nil if there are none.
+func newRedactor(schema *ytypes.Schema, rules []RedactionRule) (*redactor, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	r := &redactor{}
+	types := map[string]bool{}
+	for _, rule := range rules {
+		switch {
+		case rule.Path != "" && rule.YANGType != "":
+			return nil, fmt.Errorf("redaction rule %+v has both a path and a YANG type", rule)
+		case rule.Path != "":
+			if _, err := path.Match(rule.Path, ""); err != nil {
+				return nil, fmt.Errorf("invalid redaction path %q: %w", rule.Path, err)
+			}
+			r.globs = append(r.globs, rule.Path)
+		case rule.YANGType != "":
+			name := rule.YANGType
+			if i := strings.LastIndex(name, ":"); i >= 0 {
+				name = name[i+1:]
+			}
+			types[name] = true
+		default:
+			return nil, fmt.Errorf("redaction rule has neither a path nor a YANG type")
+		}
+	}
+	if len(types) > 0 {
+		if schema == nil || schema.RootSchema() == nil {
+			return nil, fmt.Errorf("a schema is required to resolve YANG type redaction rules")
+		}
+		r.globs = append(r.globs, typedLeafPaths(schema.RootSchema(), "", types)...)
+	}
+	return r, nil
+}
+
+// typedLeafPaths returns the schema paths of the leaves under the entry at path p whose type is in types.
+func typedLeafPaths(e *yang.Entry, p string, types map[string]bool) []string {
+	var paths []string
+	for _, child := range e.Dir {
+		childPath := p
+		// Choices and cases aren't data nodes, so they aren't part of the paths.
+		if !child.IsChoice() && !child.IsCase() {
+			childPath = p + "/" + child.Name
+		}
+		if child.IsLeaf() || child.IsLeafList() {
+			if hasType(child.Type, types) {
+				// The path is escaped, as it is matched as a glob.
+				paths = append(paths, escapeGlob(childPath))
+			}
+			continue
+		}
+		paths = append(paths, typedLeafPaths(child, childPath, types)...)
+	}
+	return paths
+}
+
+// hasType returns whether the type, or one of its union members, is in types.
+func hasType(t *yang.YangType, types map[string]bool) bool {
+	if t == nil {
+		return false
+	}
+	if types[t.Name] {
+		return true
+	}
+	for _, member := range t.Type {
+		if hasType(member, types) {
+			return true
+		}
+	}
+	return false
+}
+
+// escapeGlob escapes the special characters of path.Match in s.
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(`*?[\`, r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// match returns whether the schema path matches one of the globs.
+func (r *redactor) match(p string) bool {
+	for _, g := range r.globs {
+		if ok, _ := path.Match(g, p); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// matchElems returns whether the schema path with the elements, or one of its ancestors, matches one of the globs.
+func (r *redactor) matchElems(elems []string) bool {
+	var p string
+	for _, e := range elems {
+		p += "/" + e
+		if r.match(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaPath returns the names of the elements of the paths, which are concatenated.
+func schemaPath(paths ...*gpb.Path) []string {
+	var elems []string
+	for _, p := range paths {
+		for _, e := range p.GetElem() {
+			elems = append(elems, e.GetName())
+		}
+	}
+	return elems
+}
+
+// typedValue returns the value at the concatenated paths, with the values of the sensitive leaves masked.
+// A nil redactor returns the value as is.
+func (r *redactor) typedValue(tv *gpb.TypedValue, paths ...*gpb.Path) *gpb.TypedValue {
+	if r == nil || tv == nil {
+		return tv
+	}
+	elems := schemaPath(paths...)
+	if r.matchElems(elems) {
+		return &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: redactedText}}
+	}
+	switch v := tv.GetValue().(type) {
+	case *gpb.TypedValue_JsonIetfVal:
+		if b, ok := r.redactJSON(elems, v.JsonIetfVal); ok {
+			return &gpb.TypedValue{Value: &gpb.TypedValue_JsonIetfVal{JsonIetfVal: b}}
+		}
+	case *gpb.TypedValue_JsonVal:
+		if b, ok := r.redactJSON(elems, v.JsonVal); ok {
+			return &gpb.TypedValue{Value: &gpb.TypedValue_JsonVal{JsonVal: b}}
+		}
+	}
+	return tv
+}
+
+// redactJSON returns the JSON value at the schema path with the values of the sensitive leaves masked,
+// and whether any value was masked.
+func (r *redactor) redactJSON(elems []string, b []byte) ([]byte, bool) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, false
+	}
+	if !r.redactJSONValue("/"+strings.Join(elems, "/"), v) {
+		return nil, false
+	}
+	redacted, err := json.Marshal(v)
+	if err != nil {
+		return nil, false
+	}
+	return redacted, true
+}
+
+// redactJSONValue masks the members of the decoded JSON value at the schema path p
+// that match a glob, and returns whether any member was masked.
+func (r *redactor) redactJSONValue(p string, v any) bool {
+	var redacted bool
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			// Members of RFC7951 JSON may be qualified with their module name.
+			name := k
+			if i := strings.LastIndex(name, ":"); i >= 0 {
+				name = name[i+1:]
+			}
+			childPath := strings.TrimSuffix(p, "/") + "/" + name
+			if r.match(childPath) {
+				v[k] = redactedText
+				redacted = true
+				continue
+			}
+			if r.redactJSONValue(childPath, child) {
+				redacted = true
+			}
+		}
+	case []any:
+		// The entries of lists and leaf-lists have the path of the list.
+		for _, entry := range v {
+			if r.redactJSONValue(p, entry) {
+				redacted = true
+			}
+		}
+	}
+	return redacted
+}
+
+// message returns a copy of a gNMI message with the values of the sensitive leaves masked.
+// Messages without values are returned as is, as are all messages if the redactor is nil.
+func (r *redactor) message(m proto.Message) proto.Message {
+	if r == nil {
+		return m
+	}
+	redactUpdates := func(prefix *gpb.Path, updates []*gpb.Update) {
+		for _, u := range updates {
+			u.Val = r.typedValue(u.GetVal(), prefix, u.GetPath())
+		}
+	}
+	switch m := m.(type) {
+	case *gpb.SetRequest:
+		m = proto.Clone(m).(*gpb.SetRequest)
+		redactUpdates(m.GetPrefix(), m.GetReplace())
+		redactUpdates(m.GetPrefix(), m.GetUpdate())
+		redactUpdates(m.GetPrefix(), m.GetUnionReplace())
+		return m
+	case *gpb.SubscribeResponse:
+		m = proto.Clone(m).(*gpb.SubscribeResponse)
+		redactUpdates(m.GetUpdate().GetPrefix(), m.GetUpdate().GetUpdate())
+		return m
+	case *gpb.GetResponse:
+		m = proto.Clone(m).(*gpb.GetResponse)
+		for _, n := range m.GetNotification() {
+			redactUpdates(n.GetPrefix(), n.GetUpdate())
+		}
+		return m
+	}
+	return m
+}
+
+// value returns the value at the path, with the values of the sensitive leaves masked.
+// The fields of GoStructs are masked by setting them to their zero value in a copy.
+// A nil redactor returns the value as is.
+func (r *redactor) value(p *gpb.Path, val any) any {
+	if r == nil {
+		return val
+	}
+	elems := schemaPath(p)
+	if r.matchElems(elems) {
+		return redactedText
+	}
+	gs, ok := val.(ygot.GoStruct)
+	if !ok || reflect.ValueOf(gs).IsNil() {
+		return val
+	}
+	cp, err := ygot.DeepCopy(gs)
+	if err != nil {
+		return redactedText
+	}
+	r.redactStruct("/"+strings.Join(elems, "/"), reflect.ValueOf(cp).Elem())
+	return cp
+}
+
+// redactStruct zeroes the fields of the GoStruct at the schema path p that match a glob.
+// Both the paths and the shadow paths of the fields are matched, so config and state values are masked alike.
+func (r *redactor) redactStruct(p string, v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		var fieldPaths []string
+		for _, key := range []string{"path", "shadow-path"} {
+			if tag, ok := v.Type().Field(i).Tag.Lookup(key); ok {
+				fieldPaths = append(fieldPaths, strings.Split(tag, "|")...)
+			}
+		}
+		field := v.Field(i)
+		for _, fieldPath := range fieldPaths {
+			childPath := strings.TrimSuffix(p, "/") + "/" + strings.TrimPrefix(fieldPath, "/")
+			if r.matchPrefixes(p, childPath) {
+				field.Set(reflect.Zero(field.Type()))
+				break
+			}
+			switch field.Kind() {
+			case reflect.Ptr:
+				if !field.IsNil() && field.Elem().Kind() == reflect.Struct {
+					r.redactStruct(childPath, field.Elem())
+				}
+			case reflect.Map:
+				for _, k := range field.MapKeys() {
+					if entry := field.MapIndex(k); entry.Kind() == reflect.Ptr && !entry.IsNil() && entry.Elem().Kind() == reflect.Struct {
+						r.redactStruct(childPath, entry.Elem())
+					}
+				}
+			}
+		}
+	}
+}
+
+// matchPrefixes returns whether childPath, or one of its ancestors below p, matches a glob.
+// Field paths of compressed GoStructs may have several elements.
+func (r *redactor) matchPrefixes(p, childPath string) bool {
+	elems := strings.Split(strings.TrimPrefix(childPath, strings.TrimSuffix(p, "/")+"/"), "/")
+	cur := strings.TrimSuffix(p, "/")
+	for _, e := range elems {
+		cur += "/" + e
+		if r.match(cur) {
+			return true
+		}
+	}
+	return false
+}
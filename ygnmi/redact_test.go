@@ -0,0 +1,195 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/openconfig/ygnmi/exampleoc"
+	"github.com/openconfig/ygnmi/exampleoc/exampleocpath"
+	"github.com/openconfig/ygnmi/internal/testutil"
+	"github.com/openconfig/ygnmi/ygnmi"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+const secret = "hunter2"
+
+func TestWithRedactionRules(t *testing.T) {
+	schema, err := exampleoc.Schema()
+	if err != nil {
+		t.Fatalf("Schema() returned unexpected error: %v", err)
+	}
+	tests := []struct {
+		desc    string
+		schema  bool
+		rules   []ygnmi.RedactionRule
+		wantErr string
+	}{{
+		desc:   "path and type rules",
+		rules:  []ygnmi.RedactionRule{{Path: "/parent/child/*/one"}, {YANGType: "oc-types:ieeefloat32"}},
+		schema: true,
+	}, {
+		desc:    "type rule without schema",
+		rules:   []ygnmi.RedactionRule{{YANGType: "ieeefloat32"}},
+		wantErr: "schema is required",
+	}, {
+		desc:    "invalid glob",
+		rules:   []ygnmi.RedactionRule{{Path: "/parent/["}},
+		wantErr: "invalid redaction path",
+	}, {
+		desc:    "empty rule",
+		rules:   []ygnmi.RedactionRule{{}},
+		wantErr: "neither a path nor a YANG type",
+	}, {
+		desc:    "path and type in one rule",
+		rules:   []ygnmi.RedactionRule{{Path: "/parent", YANGType: "ieeefloat32"}},
+		wantErr: "both a path and a YANG type",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			s := schema
+			if !tt.schema {
+				s = nil
+			}
+			_, err := ygnmi.NewClient(nil, ygnmi.WithRedactionRules(s, tt.rules...))
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("NewClient() got error %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRedaction(t *testing.T) {
+	schema, err := exampleoc.Schema()
+	if err != nil {
+		t.Fatalf("Schema() returned unexpected error: %v", err)
+	}
+	rules := ygnmi.WithRedactionRules(schema, ygnmi.RedactionRule{Path: "/parent/child/*/one"}, ygnmi.RedactionRule{YANGType: "oc-types:ieeefloat32"})
+	secretVal := &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: secret}}
+
+	t.Run("DataPoint and TelemetryError", func(t *testing.T) {
+		tests := []struct {
+			desc     string
+			update   *gpb.Update
+			wantMask bool
+		}{{
+			desc:     "path rule",
+			update:   &gpb.Update{Path: testutil.GNMIPath(t, "/parent/child/config/one"), Val: secretVal},
+			wantMask: true,
+		}, {
+			desc:     "type rule",
+			update:   &gpb.Update{Path: testutil.GNMIPath(t, "/parent/child/config/five"), Val: secretVal},
+			wantMask: true,
+		}, {
+			desc: "json member",
+			update: &gpb.Update{Path: testutil.GNMIPath(t, "/parent/child/config"), Val: &gpb.TypedValue{Value: &gpb.TypedValue_JsonIetfVal{
+				JsonIetfVal: []byte(`{"one": "` + secret + `", "two": "visible"}`),
+			}}},
+			wantMask: true,
+		}, {
+			desc:   "non-sensitive leaf",
+			update: &gpb.Update{Path: testutil.GNMIPath(t, "/parent/child/config/two"), Val: secretVal},
+		}}
+		for _, tt := range tests {
+			t.Run(tt.desc, func(t *testing.T) {
+				c := newServerClient(t, &configServer{config: []*gpb.Update{tt.update}}, rules)
+				var got []string
+				validator := func(dp *ygnmi.DataPoint) error {
+					got = append(got, dp.String())
+					return nil
+				}
+				v, err := ygnmi.Lookup(context.Background(), c, exampleocpath.Root().Parent().Child().Config(), ygnmi.WithDatapointValidator(validator))
+				if err != nil {
+					t.Fatalf("Lookup() returned unexpected error: %v", err)
+				}
+				if len(got) != 1 {
+					t.Fatalf("Lookup() got datapoints %v, want 1 datapoint", got)
+				}
+				if masked := !strings.Contains(got[0], secret) && strings.Contains(got[0], "(redacted)"); masked != tt.wantMask {
+					t.Errorf("DataPoint.String() got %q, want value redacted %v", got[0], tt.wantMask)
+				}
+				if errs := v.ComplianceErrors.String(); tt.wantMask && strings.Contains(errs, secret) {
+					t.Errorf("ComplianceErrors.String() got %q, want the value redacted", errs)
+				}
+			})
+		}
+	})
+
+	t.Run("other values", func(t *testing.T) {
+		dp := &ygnmi.DataPoint{Path: testutil.GNMIPath(t, "/parent/child/config/one"), Value: secretVal}
+		if got := dp.String(); !strings.Contains(got, secret) {
+			t.Errorf("DataPoint.String() got %q, want the value of a datapoint not produced by a client", got)
+		}
+		c := newServerClient(t, &configServer{config: []*gpb.Update{{
+			Path: testutil.GNMIPath(t, "/parent/child/config/one"),
+			Val:  secretVal,
+		}}})
+		leaf, err := ygnmi.Lookup(context.Background(), c, exampleocpath.Root().Parent().Child().One().Config())
+		if err != nil {
+			t.Fatalf("Lookup() returned unexpected error: %v", err)
+		}
+		if got := leaf.String(); !strings.Contains(got, secret) {
+			t.Errorf("Value.String() got %q, want the value of a client without redaction rules", got)
+		}
+	})
+
+	t.Run("Value and logs", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug - 4}))
+		c := newServerClient(t, &configServer{config: []*gpb.Update{{
+			Path: testutil.GNMIPath(t, "/parent/child/config/one"),
+			Val:  secretVal,
+		}, {
+			Path: testutil.GNMIPath(t, "/parent/child/config/three"),
+			Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "ONE"}},
+		}}}, ygnmi.WithLogger(logger), rules)
+		ctx := context.Background()
+		child := exampleocpath.Root().Parent().Child()
+
+		if _, err := ygnmi.Replace(ctx, c, child.One().Config(), secret); err != nil {
+			t.Fatalf("Replace() returned unexpected error: %v", err)
+		}
+		leaf, err := ygnmi.Lookup(ctx, c, child.One().Config())
+		if err != nil {
+			t.Fatalf("Lookup() returned unexpected error: %v", err)
+		}
+		if got := leaf.String(); strings.Contains(got, secret) || !strings.Contains(got, "(redacted)") {
+			t.Errorf("Value.String() got %q, want the value redacted", got)
+		}
+		container, err := ygnmi.Lookup(ctx, c, child.Config())
+		if err != nil {
+			t.Fatalf("Lookup() returned unexpected error: %v", err)
+		}
+		if got := container.String(); strings.Contains(got, secret) || !strings.Contains(got, "Three:ONE") {
+			t.Errorf("Value.String() got %q, want only the sensitive leaf redacted", got)
+		}
+		if v, _ := container.Val(); v.GetOne() != secret {
+			t.Errorf("Val() got one %q, want %q: the value must not be modified", v.GetOne(), secret)
+		}
+
+		logs := buf.String()
+		if !strings.Contains(logs, "SetRequest") || !strings.Contains(logs, "Received gNMI Update") {
+			t.Fatalf("Logs are missing the request and the updates:\n%s", logs)
+		}
+		if strings.Contains(logs, secret) {
+			t.Errorf("Logs contain the sensitive value:\n%s", logs)
+		}
+	})
+}
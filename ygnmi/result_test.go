@@ -0,0 +1,109 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/ygnmi/exampleoc"
+	"github.com/openconfig/ygnmi/exampleoc/exampleocpath"
+	"github.com/openconfig/ygnmi/internal/gnmitestutil"
+	"github.com/openconfig/ygnmi/internal/testutil"
+	"github.com/openconfig/ygnmi/ygnmi"
+	"github.com/openconfig/ygot/ygot"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func TestResultOperations(t *testing.T) {
+	setClient := &gnmitestutil.SetClient{}
+	c, err := ygnmi.NewClient(setClient, ygnmi.WithTarget("dut"))
+	if err != nil {
+		t.Fatalf("Unexpected error creating client: %v", err)
+	}
+	ctx := context.Background()
+	childPath := testutil.GNMIPath(t, "/parent/child")
+	onePath := testutil.GNMIPath(t, "/parent/child/config/one")
+	threePath := testutil.GNMIPath(t, "/parent/child/config/three")
+	oneQuery := exampleocpath.Root().Parent().Child().One().Config()
+	childQuery := exampleocpath.Root().Parent().Child().Config()
+	threeQuery := exampleocpath.Root().Parent().Child().Three().Config()
+
+	// The response omits the origins, and lists the operations in a different order than the request.
+	deleteResult := &gpb.UpdateResult{Path: &gpb.Path{Elem: threePath.Elem}, Op: gpb.UpdateResult_DELETE}
+	replaceResult := &gpb.UpdateResult{Path: &gpb.Path{Elem: childPath.Elem}, Op: gpb.UpdateResult_REPLACE}
+	updateResult := &gpb.UpdateResult{Path: &gpb.Path{Elem: onePath.Elem}, Op: gpb.UpdateResult_UPDATE}
+
+	t.Run("batch", func(t *testing.T) {
+		setClient.AddResponse(&gpb.SetResponse{
+			Prefix:   &gpb.Path{Target: "dut"},
+			Response: []*gpb.UpdateResult{deleteResult, replaceResult, updateResult},
+		}, nil)
+		sb := &ygnmi.SetBatch{}
+		ygnmi.BatchUpdate(sb, oneQuery, "foo")
+		ygnmi.BatchReplace(sb, childQuery, &exampleoc.Parent_Child{One: ygot.String("bar")})
+		ygnmi.BatchDelete(sb, threeQuery)
+		ygnmi.BatchUnionReplaceCLI(sb, "openos", "open sesame")
+		res, err := sb.Set(ctx, c)
+		if err != nil {
+			t.Fatalf("Set() returned unexpected error: %v", err)
+		}
+		want := []*ygnmi.OperationResult{{
+			Path:     onePath,
+			Op:       gpb.UpdateResult_UPDATE,
+			Query:    oneQuery,
+			Response: &gpb.UpdateResult{Path: &gpb.Path{Elem: onePath.Elem, Target: "dut"}, Op: gpb.UpdateResult_UPDATE},
+		}, {
+			Path:     childPath,
+			Op:       gpb.UpdateResult_REPLACE,
+			Query:    childQuery,
+			Response: &gpb.UpdateResult{Path: &gpb.Path{Elem: childPath.Elem, Target: "dut"}, Op: gpb.UpdateResult_REPLACE},
+		}, {
+			Path:     threePath,
+			Op:       gpb.UpdateResult_DELETE,
+			Query:    threeQuery,
+			Response: &gpb.UpdateResult{Path: &gpb.Path{Elem: threePath.Elem, Target: "dut"}, Op: gpb.UpdateResult_DELETE},
+		}, {
+			Path: &gpb.Path{Origin: "openos_cli"},
+			Op:   gpb.UpdateResult_UNION_REPLACE,
+		}}
+		if diff := cmp.Diff(want, res.Operations, protocmp.Transform(), cmp.Comparer(func(a, b ygnmi.UntypedQuery) bool { return a == b })); diff != "" {
+			t.Errorf("Set() returned unexpected operations (-want,+got):\n%s", diff)
+		}
+		if res.Duration <= 0 {
+			t.Errorf("Set() returned Duration %v, want positive", res.Duration)
+		}
+	})
+
+	t.Run("single operation", func(t *testing.T) {
+		setClient.AddResponse(&gpb.SetResponse{Response: []*gpb.UpdateResult{updateResult}}, nil)
+		res, err := ygnmi.Update(ctx, c, oneQuery, "foo")
+		if err != nil {
+			t.Fatalf("Update() returned unexpected error: %v", err)
+		}
+		want := []*ygnmi.OperationResult{{
+			Path:     onePath,
+			Op:       gpb.UpdateResult_UPDATE,
+			Query:    oneQuery,
+			Response: updateResult,
+		}}
+		if diff := cmp.Diff(want, res.Operations, protocmp.Transform(), cmp.Comparer(func(a, b ygnmi.UntypedQuery) bool { return a == b })); diff != "" {
+			t.Errorf("Update() returned unexpected operations (-want,+got):\n%s", diff)
+		}
+	})
+}
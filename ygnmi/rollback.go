@@ -0,0 +1,81 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi
+
+import (
+	"context"
+	"fmt"
+)
+
+// SetWithRollback performs the gnmi.Set request with all queued operations as a client-side transaction,
+// for targets that don't support SetWithCommit.
+// Before sending the request, the current config at the path of every operation is read with Lookup.
+// If the request fails, or verify returns an error after the request succeeds, a SetRequest
+// restoring the snapshot is sent: paths with a value are replaced with it, and paths without a value are deleted.
+// verify may be nil, in which case the changes are only rolled back if the request fails.
+// The returned Result reports whether the changes were rolled back and the error of the rollback, if any.
+// Union replace operations and operations loaded with LoadSetBatch can't be rolled back,
+// and the snapshot isn't isolated from concurrent changes to the config.
+func (sb *SetBatch) SetWithRollback(ctx context.Context, c *Client, verify func(context.Context) error, opts ...Option) (*Result, error) {
+	rb := &SetBatch{}
+	for _, op := range sb.ops {
+		if op.snapshot == nil {
+			path, _ := op.gnmiPath()
+			return nil, fmt.Errorf("operation at %s can't be rolled back", pathString(path))
+		}
+		if err := op.snapshot(ctx, c, rb, opts); err != nil {
+			return nil, err
+		}
+	}
+	req, err := sb.request(ctx, c, opts)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.sendSet(ctx, req, sb.operations(), opts)
+	if res == nil {
+		// The request couldn't be split, so nothing was sent.
+		return nil, err
+	}
+	if err == nil && verify != nil {
+		if verr := verify(ctx); verr != nil {
+			err = fmt.Errorf("verification failed: %w", verr)
+		}
+	}
+	if err == nil {
+		return res, nil
+	}
+	c.log.warning(ctx, "Rolling back SetRequest", "operation", "Set", "error", err)
+	res.RolledBack = true
+	if _, res.RollbackErr = rb.Set(ctx, c, opts...); res.RollbackErr != nil {
+		return res, fmt.Errorf("%w, rollback failed: %v", err, res.RollbackErr)
+	}
+	return res, fmt.Errorf("%w, rolled back", err)
+}
+
+// snapshotFn returns a func adding the operation restoring the current config at the query to a batch.
+func snapshotFn[T any](q ConfigQuery[T]) func(context.Context, *Client, *SetBatch, []Option) error {
+	return func(ctx context.Context, c *Client, rb *SetBatch, opts []Option) error {
+		v, err := Lookup[T](ctx, c, q, opts...)
+		if err != nil {
+			return fmt.Errorf("failed to snapshot config: %w", err)
+		}
+		if val, ok := v.Val(); ok {
+			BatchReplace(rb, q, val)
+		} else {
+			BatchDelete(rb, q)
+		}
+		return nil
+	}
+}
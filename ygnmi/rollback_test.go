@@ -0,0 +1,151 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/ygnmi/exampleoc/exampleocpath"
+	"github.com/openconfig/ygnmi/internal/testutil"
+	"github.com/openconfig/ygnmi/ygnmi"
+	"github.com/openconfig/ygot/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// configServer is a gNMI server that responds to subscriptions with the values of its config,
+// and records the SetRequests it receives.
+type configServer struct {
+	setServer
+	config []*gpb.Update
+}
+
+func (s *configServer) Subscribe(srv gpb.GNMI_SubscribeServer) error {
+	req, err := srv.Recv()
+	if err != nil {
+		return err
+	}
+	for _, sub := range req.GetSubscribe().GetSubscription() {
+		for _, u := range s.config {
+			if !util.PathMatchesQuery(u.GetPath(), sub.GetPath()) {
+				continue
+			}
+			if err := srv.Send(&gpb.SubscribeResponse{Response: &gpb.SubscribeResponse_Update{Update: &gpb.Notification{
+				Timestamp: 1,
+				Update:    []*gpb.Update{u},
+			}}}); err != nil {
+				return err
+			}
+		}
+	}
+	return srv.Send(&gpb.SubscribeResponse{Response: &gpb.SubscribeResponse_SyncResponse{SyncResponse: true}})
+}
+
+func TestSetWithRollback(t *testing.T) {
+	onePath := testutil.GNMIPath(t, "/parent/child/config/one")
+	threePath := testutil.GNMIPath(t, "/parent/child/config/three")
+	config := []*gpb.Update{{
+		Path: onePath,
+		Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "old"}},
+	}}
+	newBatch := func() *ygnmi.SetBatch {
+		b := &ygnmi.SetBatch{}
+		ygnmi.BatchReplace(b, exampleocpath.Root().Parent().Child().One().Config(), "new")
+		ygnmi.BatchDelete(b, exampleocpath.Root().Parent().Child().Three().Config())
+		return b
+	}
+	rollbackReq := &gpb.SetRequest{
+		Prefix: &gpb.Path{},
+		Replace: []*gpb.Update{{
+			Path: onePath,
+			Val:  &gpb.TypedValue{Value: &gpb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`"old"`)}},
+		}},
+		Delete: []*gpb.Path{threePath},
+	}
+
+	tests := []struct {
+		desc            string
+		batch           func() *ygnmi.SetBatch
+		verify          func(context.Context) error
+		setErr          error
+		wantErr         string
+		wantRolledBack  bool
+		wantRollbackErr string
+		wantRequests    int
+	}{{
+		desc:         "success",
+		batch:        newBatch,
+		verify:       func(context.Context) error { return nil },
+		wantRequests: 1,
+	}, {
+		desc:           "verification fails",
+		batch:          newBatch,
+		verify:         func(context.Context) error { return fmt.Errorf("lost connectivity") },
+		wantErr:        "verification failed: lost connectivity, rolled back",
+		wantRolledBack: true,
+		wantRequests:   2,
+	}, {
+		desc:            "set and rollback fail",
+		batch:           newBatch,
+		setErr:          status.Error(codes.Internal, "set failed"),
+		wantErr:         "rollback failed",
+		wantRolledBack:  true,
+		wantRollbackErr: "set failed",
+		wantRequests:    2,
+	}, {
+		desc: "union replace",
+		batch: func() *ygnmi.SetBatch {
+			b := &ygnmi.SetBatch{}
+			ygnmi.BatchUnionReplace(b, exampleocpath.Root().Parent().Child().One().Config(), "new")
+			return b
+		},
+		wantErr: "operation at /parent/child/config/one can't be rolled back",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			srv := &configServer{setServer: setServer{err: tt.setErr}, config: config}
+			c := newServerClient(t, srv)
+			res, err := tt.batch().SetWithRollback(context.Background(), c, tt.verify)
+			if diff := errdiff.Substring(err, tt.wantErr); diff != "" {
+				t.Fatalf("SetWithRollback() returned unexpected diff: %s", diff)
+			}
+			reqs := srv.Requests()
+			if len(reqs) != tt.wantRequests {
+				t.Fatalf("Number of SetRequests got %d, want %d", len(reqs), tt.wantRequests)
+			}
+			if res == nil {
+				return
+			}
+			if res.RolledBack != tt.wantRolledBack {
+				t.Errorf("SetWithRollback() got RolledBack %v, want %v", res.RolledBack, tt.wantRolledBack)
+			}
+			if diff := errdiff.Substring(res.RollbackErr, tt.wantRollbackErr); diff != "" {
+				t.Errorf("SetWithRollback() returned unexpected RollbackErr: %s", diff)
+			}
+			if tt.wantRolledBack {
+				if diff := cmp.Diff(rollbackReq, reqs[1], protocmp.Transform()); diff != "" {
+					t.Errorf("Rollback SetRequest returned unexpected diff (-want,+got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
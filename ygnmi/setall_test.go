@@ -0,0 +1,145 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/ygnmi/exampleoc"
+	"github.com/openconfig/ygnmi/exampleoc/exampleocpath"
+	"github.com/openconfig/ygnmi/internal/testutil"
+	"github.com/openconfig/ygnmi/ygnmi"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func TestSetAll(t *testing.T) {
+	config := []*gpb.Update{{
+		Path: testutil.GNMIPath(t, "/model/a/single-key[key=foo]/config/key"),
+		Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "foo"}},
+	}, {
+		Path: testutil.GNMIPath(t, "/model/a/single-key[key=foo]/config/value"),
+		Val:  &gpb.TypedValue{Value: &gpb.TypedValue_IntVal{IntVal: 1}},
+	}, {
+		Path: testutil.GNMIPath(t, "/model/a/single-key[key=bar]/config/key"),
+		Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "bar"}},
+	}, {
+		Path: testutil.GNMIPath(t, "/model/a/single-key[key=bar]/config/value"),
+		Val:  &gpb.TypedValue{Value: &gpb.TypedValue_IntVal{IntVal: 2}},
+	}, {
+		// The value of baz is unset.
+		Path: testutil.GNMIPath(t, "/model/a/single-key[key=baz]/config/key"),
+		Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "baz"}},
+	}}
+	keys := exampleocpath.Root().Model().SingleKeyAny()
+
+	tests := []struct {
+		desc           string
+		op             func(*ygnmi.Client) (*ygnmi.Result, error)
+		wantErr        string
+		wantNotPresent bool
+		wantRequest    *gpb.SetRequest
+	}{{
+		desc: "update all leaves",
+		op: func(c *ygnmi.Client) (*ygnmi.Result, error) {
+			return ygnmi.UpdateAll(context.Background(), c, keys.Value().Config(), 10, nil)
+		},
+		wantRequest: &gpb.SetRequest{
+			Prefix: &gpb.Path{},
+			Update: []*gpb.Update{{
+				Path: testutil.GNMIPath(t, "/model/a/single-key[key=bar]/config/value"),
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`"10"`)}},
+			}, {
+				Path: testutil.GNMIPath(t, "/model/a/single-key[key=baz]/config/value"),
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`"10"`)}},
+			}, {
+				Path: testutil.GNMIPath(t, "/model/a/single-key[key=foo]/config/value"),
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`"10"`)}},
+			}},
+		},
+	}, {
+		desc: "update unset leaves",
+		op: func(c *ygnmi.Client) (*ygnmi.Result, error) {
+			return ygnmi.UpdateAll(context.Background(), c, keys.Value().Config(), 10, func(v *ygnmi.Value[int64]) bool {
+				return !v.IsPresent()
+			})
+		},
+		wantRequest: &gpb.SetRequest{
+			Prefix: &gpb.Path{},
+			Update: []*gpb.Update{{
+				Path: testutil.GNMIPath(t, "/model/a/single-key[key=baz]/config/value"),
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`"10"`)}},
+			}},
+		},
+	}, {
+		desc: "delete matching entries",
+		op: func(c *ygnmi.Client) (*ygnmi.Result, error) {
+			return ygnmi.DeleteAll(context.Background(), c, keys.Config(), func(v *ygnmi.Value[*exampleoc.Model_SingleKey]) bool {
+				val, ok := v.Val()
+				return ok && val.GetValue() == 1
+			})
+		},
+		wantRequest: &gpb.SetRequest{
+			Prefix: &gpb.Path{},
+			Delete: []*gpb.Path{testutil.GNMIPath(t, "/model/a/single-key[key=foo]")},
+		},
+	}, {
+		desc: "no matching entries",
+		op: func(c *ygnmi.Client) (*ygnmi.Result, error) {
+			return ygnmi.DeleteAll(context.Background(), c, keys.Config(), func(v *ygnmi.Value[*exampleoc.Model_SingleKey]) bool {
+				return false
+			})
+		},
+		wantErr:        "value not present",
+		wantNotPresent: true,
+	}, {
+		desc: "state query",
+		op: func(c *ygnmi.Client) (*ygnmi.Result, error) {
+			return ygnmi.DeleteAll(context.Background(), c, keys.Value().State(), nil)
+		},
+		wantErr: "only config queries can be set",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			srv := &configServer{config: config}
+			c := newServerClient(t, srv)
+			_, err := tt.op(c)
+			if diff := errdiff.Substring(err, tt.wantErr); diff != "" {
+				t.Fatalf("operation returned unexpected diff: %s", diff)
+			}
+			reqs := srv.Requests()
+			if tt.wantErr != "" {
+				if len(reqs) != 0 {
+					t.Errorf("Number of SetRequests got %d, want 0", len(reqs))
+				}
+				if got := errors.Is(err, ygnmi.ErrNotPresent); got != tt.wantNotPresent {
+					t.Errorf("errors.Is(err, ErrNotPresent) got %v, want %v", got, tt.wantNotPresent)
+				}
+				return
+			}
+			if len(reqs) != 1 {
+				t.Fatalf("Number of SetRequests got %d, want 1", len(reqs))
+			}
+			if diff := cmp.Diff(tt.wantRequest, reqs[0], protocmp.Transform()); diff != "" {
+				t.Errorf("operation sent unexpected SetRequest (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
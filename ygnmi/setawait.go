@@ -0,0 +1,166 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/openconfig/ygot/util"
+	"github.com/openconfig/ygot/ygot"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// NonConvergedLeaf is a leaf whose state didn't converge to the intended config, see SetAndAwait.
+type NonConvergedLeaf struct {
+	// Path is the state path of the leaf.
+	Path *gpb.Path
+	// Want is the intended value of the leaf.
+	Want *gpb.TypedValue
+	// Got is the last observed value of the leaf, or nil if it was never present.
+	Got *gpb.TypedValue
+	// redactor masks the values in String if they are sensitive, see WithRedactionRules.
+	redactor *redactor
+}
+
+func (l *NonConvergedLeaf) String() string {
+	got := "not present"
+	if l.Got != nil {
+		got = formatTypedValue(l.redactor.typedValue(l.Got, l.Path))
+	}
+	return fmt.Sprintf("%s: want %s, got %s", pathString(l.Path), formatTypedValue(l.redactor.typedValue(l.Want, l.Path)), got)
+}
+
+// ConvergenceError is returned by SetAndAwait when the state doesn't converge to the intended config.
+type ConvergenceError struct {
+	// Leaves are the leaves that didn't converge, sorted by path.
+	Leaves []*NonConvergedLeaf
+	// Err is the error that ended the wait, for example context.DeadlineExceeded.
+	Err error
+}
+
+func (e *ConvergenceError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "state did not converge: %v", e.Err)
+	for _, l := range e.Leaves {
+		b.WriteString("\n\t")
+		b.WriteString(l.String())
+	}
+	return b.String()
+}
+
+// Unwrap returns the error that ended the wait.
+func (e *ConvergenceError) Unwrap() error {
+	return e.Err
+}
+
+// SetAndAwait replaces the config at the query with val, then watches the state counterpart of the query
+// until it converges to val or the context is done.
+// For leaf queries, the state converges when it is equal to val. For non-leaf queries, it converges when
+// every leaf set in val has the same value in the state: state-only leaves and other leaves are ignored.
+// If the state doesn't converge, a *ConvergenceError listing the leaves that didn't converge
+// and their last observed values is returned, along with the Result of the Replace.
+// Note: the state query is derived from the config query, so this only works for OpenConfig style paths,
+// where the last container is either "config" or "state".
+func SetAndAwait[T any](ctx context.Context, c *Client, q ConfigQuery[T], val T, opts ...Option) (*Result, error) {
+	res, err := Replace(ctx, c, q, val, opts...)
+	if err != nil {
+		return nil, err
+	}
+	state, ok := configToState[T](q).(SingletonQuery[T])
+	if !ok {
+		return res, fmt.Errorf("state query of %T is not a singleton query", q)
+	}
+	var last *Value[T]
+	w := Watch(ctx, c, state, func(v *Value[T]) error {
+		last = v
+		leaves, err := nonConvergedLeaves(q, val, v, c.log.redactor())
+		if err != nil {
+			return err
+		}
+		if len(leaves) == 0 {
+			return nil
+		}
+		return Continue
+	}, opts...)
+	if _, err := w.Await(); err != nil {
+		leaves, lerr := nonConvergedLeaves(q, val, last, c.log.redactor())
+		if lerr != nil {
+			return res, fmt.Errorf("%w, failed to compare state: %v", err, lerr)
+		}
+		return res, &ConvergenceError{Leaves: leaves, Err: err}
+	}
+	return res, nil
+}
+
+// nonConvergedLeaves returns the leaves of the intended config at the query whose state value differs.
+// The state may be nil if no value was received. The values of the leaves are masked by r in their String method.
+func nonConvergedLeaves[T any](q ConfigQuery[T], val T, state *Value[T], r *redactor) ([]*NonConvergedLeaf, error) {
+	cfgPath, err := resolvePath(q.PathStruct())
+	if err != nil {
+		return nil, err
+	}
+	if q.isLeaf() {
+		if state != nil && state.present && reflect.DeepEqual(state.val, val) {
+			return nil, nil
+		}
+		leaf := &NonConvergedLeaf{Path: swapConfigStatePath(cfgPath), redactor: r}
+		if leaf.Want, err = ygot.EncodeTypedValue(val, gpb.Encoding_JSON_IETF); err != nil {
+			return nil, fmt.Errorf("failed to encode intended value: %w", err)
+		}
+		if state != nil && state.present {
+			if leaf.Got, err = ygot.EncodeTypedValue(state.val, gpb.Encoding_JSON_IETF); err != nil {
+				return nil, fmt.Errorf("failed to encode state value: %w", err)
+			}
+		}
+		return []*NonConvergedLeaf{leaf}, nil
+	}
+
+	stateGS, wantGS := ygot.GoStruct(q.goStruct()), ygot.GoStruct(q.goStruct())
+	if state != nil && state.present {
+		if gs, ok := any(state.val).(ygot.GoStruct); ok {
+			stateGS = gs
+		}
+	}
+	if gs, ok := any(val).(ygot.GoStruct); ok && !reflect.ValueOf(gs).IsNil() {
+		wantGS = gs
+	}
+	// Both GoStructs are diffed with the config paths of the leaves, which are then swapped to their state paths.
+	changes, err := diffGoStructs(stateGS, wantGS, q.isShadowPath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff state and intended config: %w", err)
+	}
+	var leaves []*NonConvergedLeaf
+	for _, change := range changes {
+		// State leaves that aren't intended are ignored.
+		if change.New == nil {
+			continue
+		}
+		path, err := util.JoinPaths(cfgPath, change.Path)
+		if err != nil {
+			return nil, err
+		}
+		leaves = append(leaves, &NonConvergedLeaf{
+			Path:     swapConfigStatePath(path),
+			Want:     change.New,
+			Got:      change.Old,
+			redactor: r,
+		})
+	}
+	return leaves, nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/ygnmi/exampleoc"
+	"github.com/openconfig/ygnmi/exampleoc/exampleocpath"
+	"github.com/openconfig/ygnmi/internal/testutil"
+	"github.com/openconfig/ygnmi/ygnmi"
+	"github.com/openconfig/ygot/ygot"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func TestSetAndAwait(t *testing.T) {
+	state := []*gpb.Update{{
+		Path: testutil.GNMIPath(t, "/parent/child/state/one"),
+		Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "foo"}},
+	}, {
+		Path: testutil.GNMIPath(t, "/parent/child/state/two"),
+		Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "bar"}},
+	}}
+	child := exampleocpath.Root().Parent().Child()
+
+	tests := []struct {
+		desc       string
+		op         func(*ygnmi.Client) (*ygnmi.Result, error)
+		wantErr    string
+		wantLeaves []string
+	}{{
+		desc: "leaf converged",
+		op: func(c *ygnmi.Client) (*ygnmi.Result, error) {
+			return ygnmi.SetAndAwait(context.Background(), c, child.One().Config(), "foo")
+		},
+	}, {
+		desc: "leaf not converged",
+		op: func(c *ygnmi.Client) (*ygnmi.Result, error) {
+			return ygnmi.SetAndAwait(context.Background(), c, child.One().Config(), "baz")
+		},
+		wantErr:    "state did not converge",
+		wantLeaves: []string{`/parent/child/state/one: want "baz", got "foo"`},
+	}, {
+		desc: "leaf not present",
+		op: func(c *ygnmi.Client) (*ygnmi.Result, error) {
+			return ygnmi.SetAndAwait(context.Background(), c, child.Three().Config(), exampleoc.Child_Three_ONE)
+		},
+		wantErr:    "state did not converge",
+		wantLeaves: []string{`/parent/child/state/three: want "ONE", got not present`},
+	}, {
+		desc: "container converged",
+		op: func(c *ygnmi.Client) (*ygnmi.Result, error) {
+			return ygnmi.SetAndAwait(context.Background(), c, child.Config(), &exampleoc.Parent_Child{One: ygot.String("foo")})
+		},
+	}, {
+		desc: "container not converged",
+		op: func(c *ygnmi.Client) (*ygnmi.Result, error) {
+			return ygnmi.SetAndAwait(context.Background(), c, child.Config(), &exampleoc.Parent_Child{
+				One:   ygot.String("baz"),
+				Three: exampleoc.Child_Three_TWO,
+			})
+		},
+		wantErr: "state did not converge",
+		wantLeaves: []string{
+			`/parent/child/state/one: want "baz", got "foo"`,
+			`/parent/child/state/three: want "TWO", got not present`,
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			srv := &configServer{config: state}
+			c := newServerClient(t, srv)
+			res, err := tt.op(c)
+			if diff := errdiff.Substring(err, tt.wantErr); diff != "" {
+				t.Fatalf("SetAndAwait() returned unexpected diff: %s", diff)
+			}
+			if res == nil {
+				t.Fatalf("SetAndAwait() returned nil Result")
+			}
+			if len(srv.Requests()) != 1 {
+				t.Errorf("Number of SetRequests got %d, want 1", len(srv.Requests()))
+			}
+			if tt.wantErr == "" {
+				return
+			}
+			var cerr *ygnmi.ConvergenceError
+			if !errors.As(err, &cerr) {
+				t.Fatalf("SetAndAwait() returned error %T, want *ConvergenceError", err)
+			}
+			var got []string
+			for _, l := range cerr.Leaves {
+				got = append(got, l.String())
+			}
+			if diff := cmp.Diff(tt.wantLeaves, got); diff != "" {
+				t.Errorf("SetAndAwait() returned unexpected non-converged leaves (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/openconfig/ygot/util"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// Request returns the SetRequest that Set would send with the client and options, without sending it.
+func (sb *SetBatch) Request(ctx context.Context, c *Client, opts ...Option) (*gpb.SetRequest, error) {
+	return sb.request(ctx, c, opts)
+}
+
+// MarshalText returns the operations of the batch as a SetRequest in prototext format.
+// The values are encoded with the default options, and the request has no target.
+func (sb *SetBatch) MarshalText() ([]byte, error) {
+	req, err := sb.populate(nil)
+	if err != nil {
+		return nil, err
+	}
+	return prototext.MarshalOptions{Multiline: true}.Marshal(req)
+}
+
+// MarshalJSON returns the operations of the batch as a SetRequest in protojson format.
+// The values are encoded with the default options, and the request has no target.
+func (sb *SetBatch) MarshalJSON() ([]byte, error) {
+	req, err := sb.populate(nil)
+	if err != nil {
+		return nil, err
+	}
+	return protojson.MarshalOptions{Multiline: true}.Marshal(req)
+}
+
+// UnmarshalText replaces the operations of the batch with the operations of a SetRequest in prototext format.
+func (sb *SetBatch) UnmarshalText(b []byte) error {
+	req := &gpb.SetRequest{}
+	if err := prototext.Unmarshal(b, req); err != nil {
+		return fmt.Errorf("failed to unmarshal SetRequest: %w", err)
+	}
+	return sb.load(req)
+}
+
+// UnmarshalJSON replaces the operations of the batch with the operations of a SetRequest in protojson format.
+func (sb *SetBatch) UnmarshalJSON(b []byte) error {
+	req := &gpb.SetRequest{}
+	if err := protojson.Unmarshal(b, req); err != nil {
+		return fmt.Errorf("failed to unmarshal SetRequest: %w", err)
+	}
+	return sb.load(req)
+}
+
+// LoadSetBatch reads a batch saved with MarshalText or MarshalJSON from a file.
+// Files with the .json extension are read as protojson, other files as prototext.
+// Any SetRequest in these formats may be loaded: the paths are joined with the prefix of the request,
+// and its target and extensions are ignored.
+// The values of the loaded operations are sent as is, so Set options that affect the encoding don't apply to them,
+// and the operations can't be rolled back with SetWithRollback.
+func LoadSetBatch(name string) (*SetBatch, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	sb := &SetBatch{}
+	if filepath.Ext(name) == ".json" {
+		err = sb.UnmarshalJSON(b)
+	} else {
+		err = sb.UnmarshalText(b)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load SetBatch from %s: %w", name, err)
+	}
+	return sb, nil
+}
+
+// load replaces the operations of the batch with the operations of the SetRequest,
+// in the order in which the target applies them.
+func (sb *SetBatch) load(req *gpb.SetRequest) error {
+	var ops []*batchOp
+	add := func(p *gpb.Path, val *gpb.TypedValue, mode setOperation) error {
+		path, err := util.JoinPaths(req.GetPrefix(), p)
+		if err != nil {
+			return err
+		}
+		path.Target = ""
+		ops = append(ops, &batchOp{rawPath: path, val: val, mode: mode})
+		return nil
+	}
+	for _, p := range req.GetDelete() {
+		if err := add(p, nil, deletePath); err != nil {
+			return err
+		}
+	}
+	for _, u := range req.GetReplace() {
+		if err := add(u.GetPath(), u.GetVal(), replacePath); err != nil {
+			return err
+		}
+	}
+	for _, u := range req.GetUpdate() {
+		if err := add(u.GetPath(), u.GetVal(), updatePath); err != nil {
+			return err
+		}
+	}
+	for _, u := range req.GetUnionReplace() {
+		if err := add(u.GetPath(), u.GetVal(), unionreplacePath); err != nil {
+			return err
+		}
+	}
+	sb.ops = ops
+	return nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/ygnmi/exampleoc"
+	"github.com/openconfig/ygnmi/exampleoc/exampleocpath"
+	"github.com/openconfig/ygnmi/internal/gnmitestutil"
+	"github.com/openconfig/ygnmi/ygnmi"
+	"github.com/openconfig/ygot/ygot"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func TestSetBatchRequest(t *testing.T) {
+	setClient := &gnmitestutil.SetClient{}
+	c, err := ygnmi.NewClient(setClient, ygnmi.WithTarget("dut"))
+	if err != nil {
+		t.Fatalf("Unexpected error creating client: %v", err)
+	}
+	sb := &ygnmi.SetBatch{}
+	ygnmi.BatchUpdate(sb, exampleocpath.Root().Parent().Child().One().Config(), "foo")
+	ygnmi.BatchReplace(sb, exampleocpath.Root().Parent().Child().Config(), &exampleoc.Parent_Child{
+		One:   ygot.String("bar"),
+		Three: exampleoc.Child_Three_ONE,
+	})
+	ygnmi.BatchDelete(sb, exampleocpath.Root().Parent().Child().Three().Config())
+	ygnmi.BatchUnionReplaceCLI(sb, "openos", "open sesame")
+
+	ctx := context.Background()
+	req, err := sb.Request(ctx, c)
+	if err != nil {
+		t.Fatalf("Request() returned unexpected error: %v", err)
+	}
+	setClient.AddResponse(&gpb.SetResponse{}, nil)
+	if _, err := sb.Set(ctx, c); err != nil {
+		t.Fatalf("Set() returned unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(setClient.Requests[0], req, protocmp.Transform()); diff != "" {
+		t.Errorf("Request() returned a different request than the one sent by Set() (-sent,+got):\n%s", diff)
+	}
+
+	tests := []struct {
+		desc    string
+		file    string
+		marshal func() ([]byte, error)
+	}{{
+		desc:    "text",
+		file:    "batch.txtpb",
+		marshal: sb.MarshalText,
+	}, {
+		desc:    "json",
+		file:    "batch.json",
+		marshal: sb.MarshalJSON,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			b, err := tt.marshal()
+			if err != nil {
+				t.Fatalf("marshal returned unexpected error: %v", err)
+			}
+			name := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(name, b, 0o600); err != nil {
+				t.Fatal(err)
+			}
+			loaded, err := ygnmi.LoadSetBatch(name)
+			if err != nil {
+				t.Fatalf("LoadSetBatch() returned unexpected error: %v", err)
+			}
+			got, err := loaded.Request(ctx, c)
+			if err != nil {
+				t.Fatalf("Request() of the loaded batch returned unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(req, got, protocmp.Transform()); diff != "" {
+				t.Errorf("Request() of the loaded batch returned unexpected diff (-want,+got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("invalid file", func(t *testing.T) {
+		name := filepath.Join(t.TempDir(), "batch.txtpb")
+		if err := os.WriteFile(name, []byte("not a request"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		_, err := ygnmi.LoadSetBatch(name)
+		if d := errdiff.Substring(err, "failed to unmarshal SetRequest"); d != "" {
+			t.Errorf("LoadSetBatch() returned unexpected error: %s", d)
+		}
+	})
+}
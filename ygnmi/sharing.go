@@ -0,0 +1,240 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/openconfig/ygot/util"
+	"google.golang.org/protobuf/proto"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// subscriptionManager shares STREAM subscriptions with identical SubscribeRequests
+// between concurrent watchers of a client. Subscriptions whose paths merely overlap aren't shared.
+type subscriptionManager struct {
+	mu      sync.Mutex
+	streams map[string]*sharedStream
+}
+
+// sharedStream is an upstream subscription shared by multiple watchers.
+type sharedStream struct {
+	cancel context.CancelFunc
+	refs   int
+	// ready is closed when the upstream subscription is opened, or failed to open.
+	// demux and err are only set before ready is closed.
+	ready chan struct{}
+	demux *demux
+	err   error
+}
+
+func newSubscriptionManager() *subscriptionManager {
+	return &subscriptionManager{
+		streams: map[string]*sharedStream{},
+	}
+}
+
+// subscribe returns a subscription receiving the responses for the SubscribeRequest.
+// If there is no upstream subscription for an identical request, a new one is created.
+// Watchers of a subscription that is being opened wait for it, until their context is done.
+// The returned subscription is released when the context is done.
+func (m *subscriptionManager) subscribe(ctx context.Context, c *Client, info *RPCInfo, sr *gpb.SubscribeRequest) (gpb.GNMI_SubscribeClient, error) {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(sr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal SubscribeRequest: %w", err)
+	}
+	key := string(b)
+
+	for {
+		m.mu.Lock()
+		stream, ok := m.streams[key]
+		// The upstream subscription outlives the watcher that created it,
+		// so only keep the values of the context.
+		var upCtx context.Context
+		if ok {
+			c.log.info(ctx, 2, "Sharing existing subscription", "operation", "Subscribe", "watchers", stream.refs)
+		} else {
+			var cancel context.CancelFunc
+			upCtx, cancel = context.WithCancel(context.WithoutCancel(ctx))
+			stream = &sharedStream{cancel: cancel, ready: make(chan struct{})}
+			m.streams[key] = stream
+		}
+		stream.refs++
+		m.mu.Unlock()
+
+		if !ok {
+			m.open(ctx, upCtx, c, info, sr, key, stream)
+		}
+		select {
+		case <-stream.ready:
+		case <-ctx.Done():
+			m.release(key, stream)
+			return nil, ctx.Err()
+		}
+		if stream.err != nil {
+			m.release(key, stream)
+			// The subscription may have failed to open because the watcher that opened it gave up,
+			// so the watchers waiting for it open a new one.
+			if ok && ctx.Err() == nil {
+				continue
+			}
+			return nil, stream.err
+		}
+		sub := stream.demux.add(ctx, nil)
+		go func() {
+			<-sub.ctx.Done()
+			m.release(key, stream)
+		}()
+		return sub, nil
+	}
+}
+
+// open opens the upstream subscription of the stream with upCtx, outside of the lock of the manager,
+// giving up if ctx, the context of the watcher creating the stream, is done first.
+// A stream that fails to open is removed from the manager.
+func (m *subscriptionManager) open(ctx, upCtx context.Context, c *Client, info *RPCInfo, sr *gpb.SubscribeRequest, key string, stream *sharedStream) {
+	defer close(stream.ready)
+	stop := context.AfterFunc(ctx, stream.cancel)
+	sub, err := c.newStream(upCtx, info, sr)
+	if !stop() && err == nil {
+		// ctx was done after the stream was opened, which canceled it.
+		err = ctx.Err()
+	}
+	if err != nil {
+		stream.cancel()
+		stream.err = err
+		m.remove(key, stream)
+		return
+	}
+	stream.demux = newDemux(sub, c.log)
+	stream.demux.cache = newNotificationCache()
+	// A failed stream is no longer shared, and is closed even if watchers still hold references to it,
+	// so that new watchers open a fresh subscription.
+	stream.demux.onFail = func() {
+		m.remove(key, stream)
+		stream.cancel()
+	}
+	go stream.demux.run(upCtx)
+}
+
+// release decrements the references of the stream, and closes it when there are none left.
+func (m *subscriptionManager) release(key string, stream *sharedStream) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stream.refs--
+	if stream.refs > 0 {
+		return
+	}
+	if m.streams[key] == stream {
+		delete(m.streams, key)
+	}
+	stream.cancel()
+}
+
+// remove removes the stream from the manager so that it is no longer shared.
+func (m *subscriptionManager) remove(key string, stream *sharedStream) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.streams[key] == stream {
+		delete(m.streams, key)
+	}
+}
+
+// notificationCache contains the latest values received on a subscription,
+// so that they can be replayed to subscribers joining later.
+type notificationCache struct {
+	updates map[string]*cachedUpdate
+	synced  bool
+}
+
+// cachedUpdate is an update with its full path and timestamp.
+type cachedUpdate struct {
+	path      *gpb.Path
+	val       *gpb.TypedValue
+	timestamp int64
+}
+
+func newNotificationCache() *notificationCache {
+	return &notificationCache{
+		updates: map[string]*cachedUpdate{},
+	}
+}
+
+// update applies the response to the cache.
+func (nc *notificationCache) update(resp *gpb.SubscribeResponse) error {
+	switch v := resp.GetResponse().(type) {
+	case *gpb.SubscribeResponse_SyncResponse:
+		nc.synced = true
+	case *gpb.SubscribeResponse_Update:
+		n := v.Update
+		for _, p := range n.GetDelete() {
+			full, err := util.JoinPaths(n.GetPrefix(), p)
+			if err != nil {
+				return err
+			}
+			full.Target = ""
+			for key, u := range nc.updates {
+				if util.PathMatchesQuery(u.path, full) {
+					delete(nc.updates, key)
+				}
+			}
+		}
+		for _, u := range n.GetUpdate() {
+			full, err := util.JoinPaths(n.GetPrefix(), u.GetPath())
+			if err != nil {
+				return err
+			}
+			full.Target = ""
+			nc.updates[pathToString(full)] = &cachedUpdate{
+				path:      full,
+				val:       u.GetVal(),
+				timestamp: n.GetTimestamp(),
+			}
+		}
+	}
+	return nil
+}
+
+// replay returns responses containing the cached values, grouped in notifications by timestamp,
+// followed by a sync response if the subscription has synced.
+func (nc *notificationCache) replay() []*gpb.SubscribeResponse {
+	var updates []*cachedUpdate
+	for _, u := range nc.updates {
+		updates = append(updates, u)
+	}
+	sort.Slice(updates, func(i, j int) bool {
+		if updates[i].timestamp != updates[j].timestamp {
+			return updates[i].timestamp < updates[j].timestamp
+		}
+		return pathToString(updates[i].path) < pathToString(updates[j].path)
+	})
+	var resps []*gpb.SubscribeResponse
+	var n *gpb.Notification
+	for _, u := range updates {
+		if n == nil || n.Timestamp != u.timestamp {
+			n = &gpb.Notification{Timestamp: u.timestamp}
+			resps = append(resps, &gpb.SubscribeResponse{Response: &gpb.SubscribeResponse_Update{Update: n}})
+		}
+		n.Update = append(n.Update, &gpb.Update{Path: u.path, Val: u.val})
+	}
+	if nc.synced {
+		resps = append(resps, &gpb.SubscribeResponse{Response: &gpb.SubscribeResponse_SyncResponse{SyncResponse: true}})
+	}
+	return resps
+}
@@ -0,0 +1,241 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/openconfig/ygnmi/exampleoc/exampleocpath"
+	"github.com/openconfig/ygnmi/internal/testutil"
+	"github.com/openconfig/ygnmi/ygnmi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// pushServer is a gNMI server that sends the initial responses on every Subscribe RPC,
+// followed by the responses pushed to the updates channel.
+type pushServer struct {
+	gpb.UnimplementedGNMIServer
+	initial []*gpb.SubscribeResponse
+	updates chan *gpb.SubscribeResponse
+
+	// failFirst makes the first Subscribe RPC fail after the initial responses.
+	failFirst bool
+	// failBeforeSync makes the first Subscribe RPC fail before the sync response, if failFirst is set.
+	failBeforeSync bool
+
+	mu       sync.Mutex
+	requests int
+}
+
+func (s *pushServer) Subscribe(srv gpb.GNMI_SubscribeServer) error {
+	if _, err := srv.Recv(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.requests++
+	fail := s.failFirst && s.requests == 1
+	s.mu.Unlock()
+	for _, resp := range s.initial {
+		if fail && s.failBeforeSync && resp.GetSyncResponse() {
+			break
+		}
+		if err := srv.Send(resp); err != nil {
+			return err
+		}
+	}
+	if fail {
+		return status.Error(codes.Unavailable, "stream reset")
+	}
+	for {
+		select {
+		case resp := <-s.updates:
+			if err := srv.Send(resp); err != nil {
+				return err
+			}
+		case <-srv.Context().Done():
+			return nil
+		}
+	}
+}
+
+func (s *pushServer) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+func TestSubscriptionSharing(t *testing.T) {
+	leafPath := testutil.GNMIPath(t, "/remote-container/state/a-leaf")
+	leafResp := func(ts int64, val string) *gpb.SubscribeResponse {
+		return &gpb.SubscribeResponse{Response: &gpb.SubscribeResponse_Update{Update: &gpb.Notification{
+			Timestamp: ts,
+			Update: []*gpb.Update{{
+				Path: leafPath,
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: val}},
+			}},
+		}}}
+	}
+	srv := &pushServer{
+		initial: []*gpb.SubscribeResponse{
+			leafResp(100, "foo"),
+			{Response: &gpb.SubscribeResponse_SyncResponse{SyncResponse: true}},
+		},
+		updates: make(chan *gpb.SubscribeResponse),
+	}
+	c := newServerClient(t, srv, ygnmi.WithSubscriptionSharing())
+	q := exampleocpath.Root().RemoteContainer().ALeaf().State()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// watch returns a watcher that records the values it receives until "bar",
+	// and signals when it received its first value.
+	watch := func(got *[]string, first chan struct{}) *ygnmi.Watcher[string] {
+		return ygnmi.Watch(ctx, c, q, func(v *ygnmi.Value[string]) error {
+			val, ok := v.Val()
+			if !ok {
+				return ygnmi.Continue
+			}
+			if len(*got) == 0 {
+				close(first)
+			}
+			*got = append(*got, val)
+			if val == "bar" {
+				return nil
+			}
+			return ygnmi.Continue
+		})
+	}
+
+	var got1, got2 []string
+	first1, first2 := make(chan struct{}), make(chan struct{})
+	w1 := watch(&got1, first1)
+	<-first1
+	// The second watcher joins after the initial sync, so it receives the cached value.
+	w2 := watch(&got2, first2)
+	<-first2
+	srv.updates <- leafResp(101, "bar")
+
+	for _, w := range []*ygnmi.Watcher[string]{w1, w2} {
+		if _, err := w.Await(); err != nil {
+			t.Fatalf("Await() returned unexpected error: %v", err)
+		}
+	}
+	want := []string{"foo", "bar"}
+	for i, got := range [][]string{got1, got2} {
+		if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+			t.Errorf("watcher %d got values %v, want %v", i+1, got, want)
+		}
+	}
+	if got := srv.Requests(); got != 1 {
+		t.Errorf("server got %d Subscribe RPCs, want 1", got)
+	}
+}
+
+func TestSubscriptionSharingFailedStream(t *testing.T) {
+	srv := &pushServer{
+		initial: []*gpb.SubscribeResponse{{
+			Response: &gpb.SubscribeResponse_Update{Update: &gpb.Notification{
+				Timestamp: 100,
+				Update: []*gpb.Update{{
+					Path: testutil.GNMIPath(t, "/remote-container/state/a-leaf"),
+					Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "foo"}},
+				}},
+			}},
+		}, {
+			Response: &gpb.SubscribeResponse_SyncResponse{SyncResponse: true},
+		}},
+		updates:   make(chan *gpb.SubscribeResponse),
+		failFirst: true,
+	}
+	c := newServerClient(t, srv, ygnmi.WithSubscriptionSharing())
+	q := exampleocpath.Root().RemoteContainer().ALeaf().State()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// The first watcher keeps its context, and so its reference to the stream, after the stream fails.
+	if _, err := ygnmi.Watch(ctx, c, q, func(*ygnmi.Value[string]) error { return ygnmi.Continue }).Await(); status.Code(err) != codes.Unavailable {
+		t.Fatalf("Await() got error %v, want Unavailable", err)
+	}
+	// The second watcher opens a new stream instead of joining the failed one.
+	if _, err := ygnmi.Await(ctx, c, q, "foo"); err != nil {
+		t.Fatalf("Await() returned unexpected error: %v", err)
+	}
+	if got := srv.Requests(); got != 2 {
+		t.Errorf("server got %d Subscribe RPCs, want 2", got)
+	}
+}
+
+func TestSubscriptionSharingHungOpen(t *testing.T) {
+	srv := &pushServer{
+		initial: []*gpb.SubscribeResponse{{
+			Response: &gpb.SubscribeResponse_Update{Update: &gpb.Notification{
+				Timestamp: 100,
+				Update: []*gpb.Update{{
+					Path: testutil.GNMIPath(t, "/remote-container/state/a-leaf"),
+					Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "foo"}},
+				}},
+			}},
+		}, {
+			Response: &gpb.SubscribeResponse_SyncResponse{SyncResponse: true},
+		}},
+		updates: make(chan *gpb.SubscribeResponse),
+	}
+	// The first Subscribe RPC hangs until its context is done.
+	var calls atomic.Int32
+	hung := make(chan struct{})
+	hang := ygnmi.StreamInterceptor(func(ctx context.Context, info *ygnmi.RPCInfo, req *gpb.SubscribeRequest, streamer ygnmi.Streamer) (gpb.GNMI_SubscribeClient, error) {
+		if calls.Add(1) == 1 {
+			close(hung)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return streamer(ctx, req)
+	})
+	c := newServerClient(t, srv, ygnmi.WithSubscriptionSharing(), ygnmi.WithInterceptors(hang))
+	q := exampleocpath.Root().RemoteContainer().ALeaf().State()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	hungCtx, hungCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer hungCancel()
+	hungErr := make(chan error, 1)
+	go func() {
+		_, err := ygnmi.Await(hungCtx, c, q, "foo")
+		hungErr <- err
+	}()
+	<-hung
+	// Other subscriptions are opened while the first one hangs.
+	if _, err := ygnmi.Await(ctx, c, q, "foo", ygnmi.WithSampleInterval(time.Second)); err != nil {
+		t.Fatalf("Await() returned unexpected error: %v", err)
+	}
+	// The hung subscription ends with the deadline of its watcher.
+	if err := <-hungErr; err == nil {
+		t.Fatalf("Await() of the hung subscription returned no error")
+	}
+	// The hung subscription is no longer shared, so a new watcher opens a new one.
+	if _, err := ygnmi.Await(ctx, c, q, "foo"); err != nil {
+		t.Fatalf("Await() returned unexpected error: %v", err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("Subscribe RPCs got %d, want 3", got)
+	}
+}
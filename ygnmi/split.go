@@ -0,0 +1,271 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// split splits the SetRequest according to the policy.
+// A nil policy returns the request unchanged.
+func (p *SetSplitPolicy) split(req *gpb.SetRequest) ([]*gpb.SetRequest, error) {
+	if p == nil || (p.MaxValueBytes <= 0 && p.MaxRequestBytes <= 0) {
+		return []*gpb.SetRequest{req}, nil
+	}
+	var replaces, replaceUpdates, updates []*gpb.Update
+	for _, u := range req.GetReplace() {
+		vals, err := p.splitValue(u, true)
+		if err != nil {
+			return nil, err
+		}
+		replaces = append(replaces, &gpb.Update{Path: u.GetPath(), Val: vals[0]})
+		for _, v := range vals[1:] {
+			replaceUpdates = append(replaceUpdates, &gpb.Update{Path: u.GetPath(), Val: v})
+		}
+	}
+	for _, u := range req.GetUpdate() {
+		vals, err := p.splitValue(u, true)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range vals {
+			updates = append(updates, &gpb.Update{Path: u.GetPath(), Val: v})
+		}
+	}
+	for _, u := range req.GetUnionReplace() {
+		if _, err := p.splitValue(u, false); err != nil {
+			return nil, err
+		}
+	}
+
+	first := &gpb.SetRequest{
+		Prefix:       req.GetPrefix(),
+		Delete:       req.GetDelete(),
+		Replace:      replaces,
+		Update:       replaceUpdates,
+		UnionReplace: req.GetUnionReplace(),
+		Extension:    req.GetExtension(),
+	}
+	if p.MaxRequestBytes <= 0 {
+		first.Update = append(first.Update, updates...)
+		return []*gpb.SetRequest{first}, nil
+	}
+	if size := proto.Size(first); size > p.MaxRequestBytes {
+		return nil, fmt.Errorf("cannot split SetRequest to %d bytes: its deletes, replaces and union replaces take %d bytes and can't span several SetRequests", p.MaxRequestBytes, size)
+	}
+
+	updateField := req.ProtoReflect().Descriptor().Fields().ByName("update").Number()
+	reqs := []*gpb.SetRequest{first}
+	cur, size := first, proto.Size(first)
+	for _, u := range updates {
+		entrySize := fieldSize(updateField, u)
+		if size+entrySize > p.MaxRequestBytes {
+			cur = &gpb.SetRequest{Prefix: req.GetPrefix(), Extension: req.GetExtension()}
+			size = proto.Size(cur)
+			if size+entrySize > p.MaxRequestBytes {
+				return nil, fmt.Errorf("cannot split SetRequest to %d bytes: update at %s takes %d bytes", p.MaxRequestBytes, pathString(u.GetPath()), entrySize)
+			}
+			reqs = append(reqs, cur)
+		}
+		cur.Update = append(cur.Update, u)
+		size += entrySize
+	}
+	return reqs, nil
+}
+
+// fieldSize returns the size of the message encoded as an element of a repeated field.
+func fieldSize(num protoreflect.FieldNumber, m proto.Message) int {
+	return protowire.SizeTag(num) + protowire.SizeBytes(proto.Size(m))
+}
+
+// splitValue returns the values an Update or Replace entry is split into according to MaxValueBytes.
+// If the value doesn't need to be split, it is returned as is.
+// If splittable is false, values over the limit return an error.
+func (p *SetSplitPolicy) splitValue(u *gpb.Update, splittable bool) ([]*gpb.TypedValue, error) {
+	val := u.GetVal()
+	if p.MaxValueBytes <= 0 {
+		return []*gpb.TypedValue{val}, nil
+	}
+	var b []byte
+	var newVal func([]byte) *gpb.TypedValue
+	switch v := val.GetValue().(type) {
+	case *gpb.TypedValue_JsonIetfVal:
+		b = v.JsonIetfVal
+		newVal = func(b []byte) *gpb.TypedValue {
+			return &gpb.TypedValue{Value: &gpb.TypedValue_JsonIetfVal{JsonIetfVal: b}}
+		}
+	case *gpb.TypedValue_JsonVal:
+		b = v.JsonVal
+		newVal = func(b []byte) *gpb.TypedValue {
+			return &gpb.TypedValue{Value: &gpb.TypedValue_JsonVal{JsonVal: b}}
+		}
+	default:
+		if size := proto.Size(val); size > p.MaxValueBytes {
+			return nil, fmt.Errorf("cannot split value at %s of %d bytes: only JSON values can be split", pathString(u.GetPath()), size)
+		}
+		return []*gpb.TypedValue{val}, nil
+	}
+	if len(b) <= p.MaxValueBytes {
+		return []*gpb.TypedValue{val}, nil
+	}
+	if !splittable {
+		return nil, fmt.Errorf("cannot split value at %s of %d bytes: union replace values can't be split", pathString(u.GetPath()), len(b))
+	}
+
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	var obj any
+	if err := d.Decode(&obj); err != nil {
+		return nil, fmt.Errorf("cannot split value at %s: %v", pathString(u.GetPath()), err)
+	}
+	parts, err := splitJSON(obj, p.MaxValueBytes)
+	if err != nil {
+		return nil, fmt.Errorf("cannot split value at %s of %d bytes: %v", pathString(u.GetPath()), len(b), err)
+	}
+	var vals []*gpb.TypedValue
+	for _, part := range parts {
+		pb, err := marshalJSON(part)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, newVal(pb))
+	}
+	return vals, nil
+}
+
+// jsonMember is a member of a JSON object, with the size of its encoding as "name":value.
+type jsonMember struct {
+	name string
+	val  any
+	size int
+}
+
+// splitJSON splits a JSON object into objects of at most max bytes, whose union is the object.
+// Members that are too large are split recursively if they are objects,
+// or split into several arrays of whole elements if they are arrays of objects, i.e. lists.
+func splitJSON(v any, max int) ([]map[string]any, error) {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%T value of %d bytes exceeds the limit", v, jsonSize(v))
+	}
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var members []jsonMember
+	for _, name := range names {
+		m := newJSONMember(name, obj[name])
+		// The size of an object with a single member includes the braces.
+		if m.size+2 <= max {
+			members = append(members, m)
+			continue
+		}
+		overhead := newJSONMember(name, nil).size - len("null") + 2
+		switch mv := m.val.(type) {
+		case map[string]any:
+			parts, err := splitJSON(mv, max-overhead)
+			if err != nil {
+				return nil, fmt.Errorf("%q: %v", name, err)
+			}
+			for _, part := range parts {
+				members = append(members, newJSONMember(name, part))
+			}
+		case []any:
+			chunks, err := splitJSONList(mv, max-overhead)
+			if err != nil {
+				return nil, fmt.Errorf("%q: %v", name, err)
+			}
+			for _, chunk := range chunks {
+				members = append(members, newJSONMember(name, chunk))
+			}
+		default:
+			return nil, fmt.Errorf("%q: %T value of %d bytes exceeds the limit", name, mv, jsonSize(mv))
+		}
+	}
+
+	var parts []map[string]any
+	var cur map[string]any
+	size := 0
+	for _, m := range members {
+		_, dup := cur[m.name]
+		if cur == nil || dup || size+1+m.size > max {
+			cur = map[string]any{}
+			parts = append(parts, cur)
+			size = 1
+		}
+		cur[m.name] = m.val
+		// Adds the size of the member, followed by a comma or the closing brace.
+		size += m.size + 1
+	}
+	return parts, nil
+}
+
+// splitJSONList splits an array of JSON objects into arrays of at most max bytes.
+// Elements are never split, since list entries are replaced as a whole.
+func splitJSONList(l []any, max int) ([][]any, error) {
+	var chunks [][]any
+	size := 0
+	for _, e := range l {
+		if _, ok := e.(map[string]any); !ok {
+			return nil, fmt.Errorf("leaf-list of %d bytes exceeds the limit", jsonSize(l))
+		}
+		eSize := jsonSize(e)
+		if eSize+2 > max {
+			return nil, fmt.Errorf("list entry of %d bytes exceeds the limit", eSize)
+		}
+		if len(chunks) == 0 || size+eSize+1 > max {
+			chunks = append(chunks, nil)
+			size = 1
+		}
+		chunks[len(chunks)-1] = append(chunks[len(chunks)-1], e)
+		size += eSize + 1
+	}
+	return chunks, nil
+}
+
+func newJSONMember(name string, val any) jsonMember {
+	return jsonMember{name: name, val: val, size: jsonSize(map[string]any{name: val}) - 2}
+}
+
+// jsonSize returns the size of the compact JSON encoding of the value.
+func jsonSize(v any) int {
+	b, err := marshalJSON(v)
+	if err != nil {
+		return 0
+	}
+	return len(b)
+}
+
+// marshalJSON encodes the value as compact JSON, without escaping HTML characters.
+func marshalJSON(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	e := json.NewEncoder(&buf)
+	e.SetEscapeHTML(false)
+	if err := e.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
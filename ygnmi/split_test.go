@@ -0,0 +1,183 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/ygnmi/exampleoc"
+	"github.com/openconfig/ygnmi/exampleoc/exampleocpath"
+	"github.com/openconfig/ygnmi/ygnmi"
+	"github.com/openconfig/ygot/ygot"
+	"github.com/openconfig/ygot/ytypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSetSplitting(t *testing.T) {
+	ctx := context.Background()
+	model := &exampleoc.Model{}
+	for i := 0; i < 5; i++ {
+		model.GetOrCreateSingleKey(fmt.Sprintf("key-%d", i)).Value = ygot.Int64(int64(i))
+	}
+	modelQuery := exampleocpath.Root().Model().Config()
+
+	tests := []struct {
+		desc         string
+		op           func(*ygnmi.Client, ...ygnmi.Option) error
+		policy       *ygnmi.SetSplitPolicy
+		serverErr    error
+		wantErr      string
+		wantRequests int
+		// wantReplaces and wantUpdates are the number of entries of the first request.
+		wantReplaces int
+		wantUpdates  int
+	}{{
+		desc: "no split needed",
+		op: func(c *ygnmi.Client, opts ...ygnmi.Option) error {
+			_, err := ygnmi.Replace(ctx, c, modelQuery, model, opts...)
+			return err
+		},
+		policy:       &ygnmi.SetSplitPolicy{MaxValueBytes: 10000, MaxRequestBytes: 10000},
+		wantRequests: 1,
+		wantReplaces: 1,
+	}, {
+		desc: "replace split into replace and updates",
+		op: func(c *ygnmi.Client, opts ...ygnmi.Option) error {
+			_, err := ygnmi.Replace(ctx, c, modelQuery, model, opts...)
+			return err
+		},
+		policy:       &ygnmi.SetSplitPolicy{MaxValueBytes: 200},
+		wantRequests: 1,
+		wantReplaces: 1,
+		wantUpdates:  2,
+	}, {
+		desc: "update split into requests",
+		op: func(c *ygnmi.Client, opts ...ygnmi.Option) error {
+			_, err := ygnmi.Update(ctx, c, modelQuery, model, opts...)
+			return err
+		},
+		policy:       &ygnmi.SetSplitPolicy{MaxValueBytes: 200, MaxRequestBytes: 250},
+		wantRequests: 3,
+		wantUpdates:  1,
+	}, {
+		desc: "replace spanning requests",
+		op: func(c *ygnmi.Client, opts ...ygnmi.Option) error {
+			_, err := ygnmi.Replace(ctx, c, modelQuery, model, opts...)
+			return err
+		},
+		policy:  &ygnmi.SetSplitPolicy{MaxValueBytes: 200, MaxRequestBytes: 250},
+		wantErr: "can't span several SetRequests",
+	}, {
+		desc: "union replace",
+		op: func(c *ygnmi.Client, opts ...ygnmi.Option) error {
+			sb := &ygnmi.SetBatch{}
+			ygnmi.BatchUnionReplace(sb, modelQuery, model)
+			_, err := sb.Set(ctx, c, opts...)
+			return err
+		},
+		policy:  &ygnmi.SetSplitPolicy{MaxValueBytes: 200},
+		wantErr: "union replace values can't be split",
+	}, {
+		desc: "scalar",
+		op: func(c *ygnmi.Client, opts ...ygnmi.Option) error {
+			_, err := ygnmi.Update(ctx, c, exampleocpath.Root().Parent().Child().One().Config(), strings.Repeat("a", 100), opts...)
+			return err
+		},
+		policy:  &ygnmi.SetSplitPolicy{MaxValueBytes: 50},
+		wantErr: "string value of 102 bytes exceeds the limit",
+	}, {
+		desc: "list entry",
+		op: func(c *ygnmi.Client, opts ...ygnmi.Option) error {
+			_, err := ygnmi.Update(ctx, c, modelQuery, model, opts...)
+			return err
+		},
+		policy:  &ygnmi.SetSplitPolicy{MaxValueBytes: 80},
+		wantErr: "list entry of",
+	}, {
+		desc: "failed request",
+		op: func(c *ygnmi.Client, opts ...ygnmi.Option) error {
+			_, err := ygnmi.Update(ctx, c, modelQuery, model, opts...)
+			return err
+		},
+		policy:       &ygnmi.SetSplitPolicy{MaxValueBytes: 200, MaxRequestBytes: 250},
+		serverErr:    status.Error(codes.Internal, "failed"),
+		wantErr:      "SetRequest 1 of 3 failed",
+		wantRequests: 1,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			srv := &setServer{err: tt.serverErr}
+			c := newServerClient(t, srv)
+			err := tt.op(c, ygnmi.WithSetSplitting(tt.policy))
+			if diff := errdiff.Substring(err, tt.wantErr); diff != "" {
+				t.Fatalf("operation returned unexpected diff: %s", diff)
+			}
+			reqs := srv.Requests()
+			if len(reqs) != tt.wantRequests {
+				t.Fatalf("Number of SetRequests got %d, want %d", len(reqs), tt.wantRequests)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			if got := len(reqs[0].GetReplace()); got != tt.wantReplaces {
+				t.Errorf("Number of replaces got %d, want %d", got, tt.wantReplaces)
+			}
+			if got := len(reqs[0].GetUpdate()); got != tt.wantUpdates {
+				t.Errorf("Number of updates got %d, want %d", got, tt.wantUpdates)
+			}
+
+			got := &exampleoc.Model{}
+			for _, req := range reqs {
+				if max := tt.policy.MaxRequestBytes; max > 0 && proto.Size(req) > max {
+					t.Errorf("SetRequest size got %d, want at most %d", proto.Size(req), max)
+				}
+				for _, u := range append(req.GetReplace(), req.GetUpdate()...) {
+					if max := tt.policy.MaxValueBytes; len(u.GetVal().GetJsonIetfVal()) > max {
+						t.Errorf("Value size got %d, want at most %d", len(u.GetVal().GetJsonIetfVal()), max)
+					}
+					if err := exampleoc.Unmarshal(u.GetVal().GetJsonIetfVal(), got, &ytypes.PreferShadowPath{}); err != nil {
+						t.Fatalf("Unmarshal() of value %s returned unexpected error: %v", u.GetVal().GetJsonIetfVal(), err)
+					}
+				}
+			}
+			if diff := cmp.Diff(model, got); diff != "" {
+				t.Errorf("Union of the split values has unexpected diff (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSetWithCommitSplitting(t *testing.T) {
+	srv := &setServer{}
+	c := newServerClient(t, srv)
+	sb := &ygnmi.SetBatch{}
+	for i := 0; i < 5; i++ {
+		ygnmi.BatchUpdate(sb, exampleocpath.Root().Model().SingleKey(fmt.Sprintf("key-%d", i)).Value().Config(), int64(i))
+	}
+	_, err := sb.SetWithCommit(context.Background(), c, 0, ygnmi.WithSetSplitting(&ygnmi.SetSplitPolicy{MaxRequestBytes: 200}))
+	if diff := errdiff.Substring(err, "commit can't span"); diff != "" {
+		t.Fatalf("SetWithCommit() returned unexpected diff: %s", diff)
+	}
+	if len(srv.Requests()) != 0 {
+		t.Errorf("Number of SetRequests got %d, want 0", len(srv.Requests()))
+	}
+}
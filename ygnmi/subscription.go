@@ -0,0 +1,389 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/openconfig/ygot/util"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// Subscription contains a collection of queries, which may have different types and roots,
+// that are subscribed to using a single STREAM subscription.
+// Use SubscriptionWatch and SubscriptionWatchAll to add queries, and then call
+// the Watch method to start the subscription.
+//
+// Each received notification is dispatched to the queries whose paths match it,
+// so the queries' paths should not overlap.
+type Subscription struct {
+	queries []*subscriptionQuery
+}
+
+// subscriptionQuery is a query added to a Subscription.
+type subscriptionQuery struct {
+	// paths are the paths to subscribe to for the query.
+	paths []PathStruct
+	// watch evaluates the query's predicate with the values received on the subscription.
+	watch func(ctx context.Context, c *Client, sub gpb.GNMI_SubscribeClient, o *opt) error
+}
+
+// NewSubscription creates an empty Subscription.
+func NewSubscription() *Subscription {
+	return &Subscription{}
+}
+
+// SubscriptionWatch adds a singleton query to the Subscription. The predicate is evaluated
+// the same way as for Watch, except that returning nil only stops the evaluation for this query.
+func SubscriptionWatch[T any](s *Subscription, q SingletonQuery[T], pred func(*Value[T]) error) {
+	s.queries = append(s.queries, &subscriptionQuery{
+		paths: q.subPaths(),
+		watch: func(ctx context.Context, c *Client, sub gpb.GNMI_SubscribeClient, o *opt) error {
+			w := &Watcher[T]{}
+			return w.receive(ctx, c, sub, q, pred, o)
+		},
+	})
+}
+
+// SubscriptionWatchAll adds a wildcard query to the Subscription. The predicate is evaluated
+// the same way as for WatchAll, except that returning nil only stops the evaluation for this query.
+func SubscriptionWatchAll[T any](s *Subscription, q WildcardQuery[T], pred func(*Value[T]) error) {
+	s.queries = append(s.queries, &subscriptionQuery{
+		paths: q.subPaths(),
+		watch: func(ctx context.Context, c *Client, sub gpb.GNMI_SubscribeClient, o *opt) error {
+			path, err := resolvePath(q.PathStruct())
+			if err != nil {
+				return err
+			}
+			w := &Watcher[T]{}
+			return w.receiveAll(ctx, c, sub, q, path, pred, o)
+		},
+	})
+}
+
+// SubscriptionWatcher represents an ongoing watch of a Subscription.
+type SubscriptionWatcher struct {
+	errCh chan error
+}
+
+// Await waits for the subscription to finish and returns the error it finished with.
+// When Await returns the watcher is closed, and Await may not be called again.
+func (w *SubscriptionWatcher) Await() error {
+	err, ok := <-w.errCh
+	if !ok {
+		return fmt.Errorf("Await already called and SubscriptionWatcher is closed")
+	}
+	close(w.errCh)
+	return err
+}
+
+// Watch starts an asynchronous STREAM subscription to the paths of all the queries in the Subscription,
+// evaluating each query's predicate with its observed values.
+// The subscription completes successfully when all predicates have returned nil.
+// If any predicate returns a non-nil error other than ygnmi.Continue, the subscription is stopped
+// and the error is returned by Await. The subscription can also be stopped by setting a deadline on
+// or canceling the context.
+// Options are applied to all the queries, WithUseGet, WithFT and WithResubscribe are not supported.
+// Stream interceptors are called once for the whole subscription, with a nil RPCInfo.Query.
+func (s *Subscription) Watch(ctx context.Context, c *Client, opts ...Option) *SubscriptionWatcher {
+	var cancel context.CancelFunc
+	ctx, cancel = context.WithCancel(ctx)
+	w := &SubscriptionWatcher{
+		errCh: make(chan error, 1),
+	}
+	resolvedOpts := resolveClientOpts(c, opts)
+	if err := s.validate(resolvedOpts); err != nil {
+		cancel()
+		w.errCh <- err
+		return w
+	}
+
+	var paths []*gpb.Path
+	var params []*SubscriptionParams
+	queryPaths := make([][]*gpb.Path, len(s.queries))
+	for i, q := range s.queries {
+		for _, ps := range q.paths {
+			p, err := resolvePath(ps)
+			if err != nil {
+				cancel()
+				w.errCh <- err
+				return w
+			}
+			paths = append(paths, p)
+			params = append(params, pathParams(ps))
+			queryPaths[i] = append(queryPaths[i], p)
+		}
+	}
+	sub, err := subscribePaths(ctx, c, nil, paths, params, gpb.SubscriptionList_STREAM, gpb.GetRequest_ALL, resolvedOpts)
+	if err != nil {
+		cancel()
+		w.errCh <- err
+		return w
+	}
+
+	d := newDemux(sub, c.log)
+	errCh := make(chan error, len(s.queries))
+	for i, q := range s.queries {
+		qsub := d.add(ctx, queryPaths[i])
+		go func() {
+			errCh <- q.watch(qsub.ctx, c, qsub, resolvedOpts)
+			qsub.close()
+		}()
+	}
+	go d.run(ctx)
+	go func() {
+		defer cancel()
+		for range s.queries {
+			if err := <-errCh; err != nil {
+				w.errCh <- err
+				return
+			}
+		}
+		w.errCh <- nil
+	}()
+	return w
+}
+
+// validate returns an error if the Subscription can't be used with the options.
+func (s *Subscription) validate(o *opt) error {
+	switch {
+	case len(s.queries) == 0:
+		return fmt.Errorf("subscription contains no queries")
+	case o.useGet:
+		return fmt.Errorf("using gnmi.Get is only valid for ONCE subscriptions")
+	case o.ft != nil:
+		return fmt.Errorf("functional translators are not supported for multiplexed subscriptions")
+	case o.resubscribe != nil:
+		return fmt.Errorf("resubscribing is not supported for multiplexed subscriptions")
+	}
+	return nil
+}
+
+// demux receives the responses from a single subscription,
+// and dispatches them to multiple subscribers by path.
+type demux struct {
+	sub gpb.GNMI_SubscribeClient
+	log *logger
+	// cache contains the latest values received, it is nil unless caching is enabled.
+	cache *notificationCache
+	// onFail is called when the subscription fails, before the subscribers are notified.
+	onFail func()
+
+	mu     sync.Mutex
+	subs   []*demuxSubscriber
+	failed bool
+	err    error
+}
+
+// newDemux creates a demux for the subscription, logging to l.
+func newDemux(sub gpb.GNMI_SubscribeClient, l *logger) *demux {
+	return &demux{sub: sub, log: l}
+}
+
+// add returns a new subscriber which receives the responses matching the paths,
+// or all responses if paths is nil.
+// If caching is enabled, the subscriber first receives the cached values.
+// The subscriber is removed once the context is cancelled or close is called.
+func (d *demux) add(ctx context.Context, paths []*gpb.Path) *demuxSubscriber {
+	ctx, cancel := context.WithCancel(ctx)
+	s := &demuxSubscriber{
+		ctx:    ctx,
+		cancel: cancel,
+		paths:  paths,
+		respCh: make(chan *gpb.SubscribeResponse),
+		done:   make(chan struct{}),
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.failed {
+		s.fail(d.err)
+		return s
+	}
+	if d.cache != nil {
+		s.pending = d.cache.replay()
+	}
+	d.subs = append(d.subs, s)
+	return s
+}
+
+// receive records the response in the cache and returns the current subscribers.
+func (d *demux) receive(resp *gpb.SubscribeResponse) ([]*demuxSubscriber, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.cache != nil {
+		if err := d.cache.update(resp); err != nil {
+			return nil, err
+		}
+	}
+	var subs []*demuxSubscriber
+	for _, s := range d.subs {
+		if s.ctx.Err() == nil {
+			subs = append(subs, s)
+		}
+	}
+	d.subs = subs
+	return append([]*demuxSubscriber{}, subs...), nil
+}
+
+// fail marks the demux as failed and returns the subscribers.
+func (d *demux) fail(err error) []*demuxSubscriber {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.failed = true
+	d.err = err
+	return d.subs
+}
+
+// run receives responses until the subscription fails or the context is cancelled.
+// Sync responses are sent to all subscribers, while the updates and deletes of a notification
+// are only sent to the subscribers with a matching path.
+// The error the subscription failed with is returned by Recv of all subscribers.
+func (d *demux) run(ctx context.Context) {
+	for {
+		resp, err := d.sub.Recv()
+		var subs []*demuxSubscriber
+		if err == nil {
+			subs, err = d.receive(resp)
+		}
+		if err != nil {
+			if d.onFail != nil {
+				d.onFail()
+			}
+			for _, s := range d.fail(err) {
+				s.fail(err)
+			}
+			return
+		}
+		for _, s := range subs {
+			sresp := resp
+			if n := resp.GetUpdate(); n != nil && s.paths != nil {
+				fn, err := filterNotification(n, s.paths)
+				if err != nil {
+					d.log.warning(ctx, "Failed to filter notification for subscriber", "path", pathString(n.GetPrefix()), "error", err)
+					continue
+				}
+				if fn == nil {
+					continue
+				}
+				sresp = &gpb.SubscribeResponse{Response: &gpb.SubscribeResponse_Update{Update: fn}}
+			}
+			select {
+			case s.respCh <- sresp:
+			case <-s.ctx.Done():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// filterNotification returns a copy of the notification containing only the updates and deletes
+// whose paths match any of the query paths. If none of them match, nil is returned.
+func filterNotification(n *gpb.Notification, queryPaths []*gpb.Path) (*gpb.Notification, error) {
+	matches := func(p *gpb.Path) (bool, error) {
+		full, err := util.JoinPaths(n.GetPrefix(), p)
+		if err != nil {
+			return false, err
+		}
+		for _, qp := range queryPaths {
+			if util.PathMatchesQuery(full, qp) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	fn := &gpb.Notification{
+		Timestamp: n.GetTimestamp(),
+		Prefix:    n.GetPrefix(),
+		Atomic:    n.GetAtomic(),
+	}
+	for _, p := range n.GetDelete() {
+		ok, err := matches(p)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			fn.Delete = append(fn.Delete, p)
+		}
+	}
+	for _, u := range n.GetUpdate() {
+		ok, err := matches(u.GetPath())
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			fn.Update = append(fn.Update, u)
+		}
+	}
+	if len(fn.Delete) == 0 && len(fn.Update) == 0 {
+		return nil, nil
+	}
+	return fn, nil
+}
+
+// demuxSubscriber is an implementation of gpb.GNMI_SubscribeClient that receives
+// the responses dispatched to it by a demux.
+type demuxSubscriber struct {
+	gpb.GNMI_SubscribeClient
+	ctx    context.Context
+	cancel context.CancelFunc
+	paths  []*gpb.Path
+	respCh chan *gpb.SubscribeResponse
+	// pending are responses returned by Recv before the ones sent on respCh.
+	pending []*gpb.SubscribeResponse
+
+	// done is closed when the demux fails with err.
+	done     chan struct{}
+	err      error
+	failOnce sync.Once
+}
+
+// Recv returns the next response dispatched to the subscriber.
+func (s *demuxSubscriber) Recv() (*gpb.SubscribeResponse, error) {
+	if len(s.pending) > 0 {
+		resp := s.pending[0]
+		s.pending = s.pending[1:]
+		return resp, nil
+	}
+	select {
+	case resp := <-s.respCh:
+		return resp, nil
+	case <-s.done:
+		return nil, s.err
+	case <-s.ctx.Done():
+		return nil, s.ctx.Err()
+	}
+}
+
+// CloseSend is noop implementation gRPC subscribe interface.
+func (s *demuxSubscriber) CloseSend() error {
+	return nil
+}
+
+// fail makes Recv return err.
+func (s *demuxSubscriber) fail(err error) {
+	s.failOnce.Do(func() {
+		s.err = err
+		close(s.done)
+	})
+}
+
+// close stops the subscriber from receiving responses.
+func (s *demuxSubscriber) close() {
+	s.cancel()
+}
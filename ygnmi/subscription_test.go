@@ -0,0 +1,204 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/ygnmi/exampleoc"
+	"github.com/openconfig/ygnmi/exampleoc/exampleocpath"
+	"github.com/openconfig/ygnmi/internal/gnmitestutil"
+	"github.com/openconfig/ygnmi/internal/testutil"
+	"github.com/openconfig/ygnmi/ygnmi"
+	"github.com/openconfig/ygot/ygot"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func TestSubscription(t *testing.T) {
+	fakeGNMI, c := newClient(t)
+	leafPath := testutil.GNMIPath(t, "/remote-container/state/a-leaf")
+	wildcardPath := testutil.GNMIPath(t, "/model/a/single-key[key=*]/state/value")
+	key10Path := testutil.GNMIPath(t, "/model/a/single-key[key=10]/state/value")
+	key11Path := testutil.GNMIPath(t, "/model/a/single-key[key=11]/state/value")
+	childPath := testutil.GNMIPath(t, "/parent/child")
+	oneStatePath := testutil.GNMIPath(t, "/parent/child/state/one")
+	oneConfigPath := testutil.GNMIPath(t, "/parent/child/config/one")
+
+	tests := []struct {
+		desc      string
+		stub      func(s *gnmitestutil.Stubber)
+		stopLeaf  string
+		wantLeaf  []string
+		wantKeys  []int64
+		wantChild []*exampleoc.Parent_Child
+		wantErr   string
+	}{{
+		desc: "all queries complete",
+		stub: func(s *gnmitestutil.Stubber) {
+			s.Notification(&gpb.Notification{
+				Timestamp: 100,
+				Update: []*gpb.Update{{
+					Path: leafPath,
+					Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "foo"}},
+				}, {
+					Path: key10Path,
+					Val:  &gpb.TypedValue{Value: &gpb.TypedValue_IntVal{IntVal: 10}},
+				}, {
+					Path: oneStatePath,
+					Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "one"}},
+				}, {
+					Path: oneConfigPath,
+					Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "config"}},
+				}},
+			}).Sync().Notification(&gpb.Notification{
+				Timestamp: 101,
+				Update: []*gpb.Update{{
+					Path: key11Path,
+					Val:  &gpb.TypedValue{Value: &gpb.TypedValue_IntVal{IntVal: 11}},
+				}, {
+					Path: leafPath,
+					Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "bar"}},
+				}},
+			})
+		},
+		stopLeaf:  "bar",
+		wantLeaf:  []string{"foo", "bar"},
+		wantKeys:  []int64{10, 11},
+		wantChild: []*exampleoc.Parent_Child{{One: ygot.String("one")}},
+	}, {
+		desc: "stream error",
+		stub: func(s *gnmitestutil.Stubber) {
+			s.Notification(&gpb.Notification{
+				Timestamp: 100,
+				Update: []*gpb.Update{{
+					Path: leafPath,
+					Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "foo"}},
+				}, {
+					Path: oneStatePath,
+					Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "one"}},
+				}},
+			}).Sync()
+		},
+		stopLeaf: "bar",
+		wantErr:  "EOF",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			tt.stub(fakeGNMI.Stub())
+			var gotLeaf []string
+			var gotKeys []int64
+			var gotChild []*exampleoc.Parent_Child
+
+			s := ygnmi.NewSubscription()
+			ygnmi.SubscriptionWatch(s, exampleocpath.Root().RemoteContainer().ALeaf().State(), func(v *ygnmi.Value[string]) error {
+				val, ok := v.Val()
+				if !ok {
+					return ygnmi.Continue
+				}
+				gotLeaf = append(gotLeaf, val)
+				if val == tt.stopLeaf {
+					return nil
+				}
+				return ygnmi.Continue
+			})
+			ygnmi.SubscriptionWatchAll(s, exampleocpath.Root().Model().SingleKeyAny().Value().State(), func(v *ygnmi.Value[int64]) error {
+				val, ok := v.Val()
+				if !ok {
+					return fmt.Errorf("value not present at %v", v.Path)
+				}
+				gotKeys = append(gotKeys, val)
+				if len(gotKeys) == 2 {
+					return nil
+				}
+				return ygnmi.Continue
+			})
+			ygnmi.SubscriptionWatch(s, exampleocpath.Root().Parent().Child().State(), func(v *ygnmi.Value[*exampleoc.Parent_Child]) error {
+				val, ok := v.Val()
+				if !ok {
+					return ygnmi.Continue
+				}
+				cp, err := ygot.DeepCopy(val)
+				if err != nil {
+					return err
+				}
+				gotChild = append(gotChild, cp.(*exampleoc.Parent_Child))
+				return nil
+			})
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			err := s.Watch(ctx, c).Await()
+			if diff := errdiff.Substring(err, tt.wantErr); diff != "" {
+				t.Fatalf("Await() returned unexpected diff: %s", diff)
+			}
+			verifySubscriptionPathsSent(t, fakeGNMI, leafPath, wildcardPath, childPath)
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.wantLeaf, gotLeaf); diff != "" {
+				t.Errorf("leaf query got unexpected values (-want,+got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantKeys, gotKeys); diff != "" {
+				t.Errorf("wildcard query got unexpected values (-want,+got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantChild, gotChild); diff != "" {
+				t.Errorf("non-leaf query got unexpected values (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSubscriptionOptions(t *testing.T) {
+	_, c := newClient(t)
+	tests := []struct {
+		desc    string
+		queries bool
+		opts    []ygnmi.Option
+		wantErr string
+	}{{
+		desc:    "no queries",
+		wantErr: "no queries",
+	}, {
+		desc:    "use get",
+		queries: true,
+		opts:    []ygnmi.Option{ygnmi.WithUseGet()},
+		wantErr: "only valid for ONCE",
+	}, {
+		desc:    "resubscribe",
+		queries: true,
+		opts:    []ygnmi.Option{ygnmi.WithResubscribe(&ygnmi.ResubscribePolicy{})},
+		wantErr: "resubscribing is not supported",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			s := ygnmi.NewSubscription()
+			if tt.queries {
+				ygnmi.SubscriptionWatch(s, exampleocpath.Root().RemoteContainer().ALeaf().State(), func(v *ygnmi.Value[string]) error {
+					return nil
+				})
+			}
+			err := s.Watch(context.Background(), c, tt.opts...).Await()
+			if diff := errdiff.Substring(err, tt.wantErr); diff != "" {
+				t.Fatalf("Await() returned unexpected diff: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// instrumentationName is the name of the tracer and the meter of the clients.
+const instrumentationName = "github.com/openconfig/ygnmi/ygnmi"
+
+// Attributes of the spans and metrics.
+const (
+	pathKey       = attribute.Key("ygnmi.path")
+	operationKey  = attribute.Key("ygnmi.operation")
+	targetKey     = attribute.Key("ygnmi.target")
+	outcomeKey    = attribute.Key("ygnmi.outcome")
+	setOpsKey     = attribute.Key("ygnmi.set.operations")
+	categoryKey   = attribute.Key("ygnmi.compliance.category")
+	outcomeOK     = "ok"
+	outcomeAbsent = "not_present"
+	outcomeError  = "error"
+)
+
+// WithTelemetry enables OpenTelemetry instrumentation of the client.
+// Each Lookup, LookupAll, Watch, WatchAll, Set and Reconciler callback produces a span, with the
+// query path, the operation, the target and the outcome ("ok", "not_present" or "error") as attributes.
+// Set spans only have a path if the Set has exactly one operation, and the number of operations
+// as the ygnmi.set.operations attribute.
+// The following metrics are recorded:
+//   - ygnmi.notifications: the number of notifications received on subscriptions.
+//   - ygnmi.compliance_errors: the number of compliance errors encountered while unmarshalling,
+//     with the category ("path", "type", "validate" or "datapoint_validate") as attribute.
+//   - ygnmi.unmarshal.duration: the time spent unmarshalling notifications into values.
+//
+// If tp or mp is nil, the global provider registered with the otel package is used.
+func WithTelemetry(tp trace.TracerProvider, mp metric.MeterProvider) ClientOption {
+	return func(c *Client) error {
+		if tp == nil {
+			tp = otel.GetTracerProvider()
+		}
+		if mp == nil {
+			mp = otel.GetMeterProvider()
+		}
+		t, err := newTelemetry(c, tp, mp)
+		if err != nil {
+			return err
+		}
+		c.telemetry = t
+		return nil
+	}
+}
+
+// telemetry records the spans and metrics of a client.
+// A nil telemetry records nothing.
+type telemetry struct {
+	client            *Client
+	tracer            trace.Tracer
+	notifications     metric.Int64Counter
+	complianceErrors  metric.Int64Counter
+	unmarshalDuration metric.Float64Histogram
+}
+
+func newTelemetry(c *Client, tp trace.TracerProvider, mp metric.MeterProvider) (*telemetry, error) {
+	meter := mp.Meter(instrumentationName)
+	notifications, err := meter.Int64Counter("ygnmi.notifications",
+		metric.WithDescription("Number of notifications received on subscriptions."),
+		metric.WithUnit("{notification}"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create notifications counter: %w", err)
+	}
+	complianceErrors, err := meter.Int64Counter("ygnmi.compliance_errors",
+		metric.WithDescription("Number of compliance errors encountered while unmarshalling notifications."),
+		metric.WithUnit("{error}"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create compliance errors counter: %w", err)
+	}
+	unmarshalDuration, err := meter.Float64Histogram("ygnmi.unmarshal.duration",
+		metric.WithDescription("Time spent unmarshalling notifications into values."),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create unmarshal duration histogram: %w", err)
+	}
+	return &telemetry{
+		client:            c,
+		tracer:            tp.Tracer(instrumentationName),
+		notifications:     notifications,
+		complianceErrors:  complianceErrors,
+		unmarshalDuration: unmarshalDuration,
+	}, nil
+}
+
+// start starts the span of an operation on the query.
+func (t *telemetry) start(ctx context.Context, op string, q UntypedQuery) (context.Context, trace.Span) {
+	if t == nil {
+		return ctx, noop.Span{}
+	}
+	// Queries whose path can't be resolved fail later, and their span has no path.
+	path, _ := resolvePath(q.PathStruct())
+	return t.startPath(ctx, op, path)
+}
+
+// startSet starts the span of a Set with the operations.
+func (t *telemetry) startSet(ctx context.Context, ops []*OperationResult) (context.Context, trace.Span) {
+	if t == nil {
+		return ctx, noop.Span{}
+	}
+	var path *gpb.Path
+	if len(ops) == 1 {
+		path = ops[0].Path
+	}
+	return t.startPath(ctx, "Set", path, setOpsKey.Int(len(ops)))
+}
+
+// startPath starts the span of an operation on the path, which may be nil.
+func (t *telemetry) startPath(ctx context.Context, op string, path *gpb.Path, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if t == nil {
+		return ctx, noop.Span{}
+	}
+	attrs = append(attrs, operationKey.String(op), targetKey.String(t.client.target))
+	if path != nil {
+		attrs = append(attrs, pathKey.String(pathString(path)))
+	}
+	return t.tracer.Start(ctx, "ygnmi."+op, trace.WithAttributes(attrs...))
+}
+
+// endSpan ends the span of an operation with the outcome and the error it ended with.
+func endSpan(span trace.Span, outcome string, err error) {
+	span.SetAttributes(outcomeKey.String(outcome))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// outcomeOf returns the outcome of an operation that ended with the error.
+func outcomeOf(err error) string {
+	switch {
+	case err == nil:
+		return outcomeOK
+	case errors.Is(err, ErrNotPresent):
+		return outcomeAbsent
+	default:
+		return outcomeError
+	}
+}
+
+// countNotifications returns a subscription counting the notifications received on sub.
+func (t *telemetry) countNotifications(sub gpb.GNMI_SubscribeClient) gpb.GNMI_SubscribeClient {
+	if t == nil {
+		return sub
+	}
+	return &notificationCounter{GNMI_SubscribeClient: sub, t: t}
+}
+
+// notificationCounter is a subscription counting the notifications it receives.
+type notificationCounter struct {
+	gpb.GNMI_SubscribeClient
+	t *telemetry
+}
+
+func (n *notificationCounter) Recv() (*gpb.SubscribeResponse, error) {
+	resp, err := n.GNMI_SubscribeClient.Recv()
+	if resp.GetUpdate() != nil {
+		n.t.notifications.Add(context.Background(), 1, metric.WithAttributes(targetKey.String(n.t.client.target)))
+	}
+	return resp, err
+}
+
+// recordUnmarshal records the duration of an unmarshal that started at start,
+// and the compliance errors it encountered, which may be nil.
+func (t *telemetry) recordUnmarshal(start time.Time, errs *ComplianceErrors) {
+	if t == nil {
+		return
+	}
+	ctx := context.Background()
+	target := targetKey.String(t.client.target)
+	t.unmarshalDuration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(target))
+	if errs == nil {
+		return
+	}
+	for category, n := range map[string]int{
+		"path":               len(errs.PathErrors),
+		"type":               len(errs.TypeErrors),
+		"validate":           len(errs.ValidateErrors),
+		"datapoint_validate": len(errs.DataPointValidateErrors),
+	} {
+		if n > 0 {
+			t.complianceErrors.Add(ctx, int64(n), metric.WithAttributes(target, categoryKey.String(category)))
+		}
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/ygnmi/exampleoc"
+	"github.com/openconfig/ygnmi/exampleoc/exampleocpath"
+	"github.com/openconfig/ygnmi/internal/testutil"
+	"github.com/openconfig/ygnmi/ygnmi"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// telemetrySpan is the name and attributes of a span.
+type telemetrySpan struct {
+	Name  string
+	Attrs map[string]string
+}
+
+func newTelemetryClient(t *testing.T, srv gpb.GNMIServer) (*ygnmi.Client, *tracetest.InMemoryExporter, *sdkmetric.ManualReader) {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	reader := sdkmetric.NewManualReader()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	c := newServerClient(t, srv, ygnmi.WithTarget("dut"), ygnmi.WithTelemetry(tp, mp))
+	return c, exporter, reader
+}
+
+// exportedSpans returns the names and the string attributes of the exported spans.
+func exportedSpans(exporter *tracetest.InMemoryExporter) []telemetrySpan {
+	var spans []telemetrySpan
+	for _, s := range exporter.GetSpans() {
+		span := telemetrySpan{Name: s.Name, Attrs: map[string]string{}}
+		for _, kv := range s.Attributes {
+			span.Attrs[string(kv.Key)] = kv.Value.Emit()
+		}
+		spans = append(spans, span)
+	}
+	return spans
+}
+
+// sums returns the values of the int64 sums of the metric by the attribute, or by "" if attr is empty.
+func sums(t *testing.T, reader *sdkmetric.ManualReader, name string, attr attribute.Key) map[string]int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect() returned unexpected error: %v", err)
+	}
+	got := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			if !ok {
+				t.Fatalf("Metric %s has data %T, want metricdata.Sum[int64]", name, m.Data)
+			}
+			for _, dp := range sum.DataPoints {
+				v, _ := dp.Attributes.Value(attr)
+				got[v.Emit()] += dp.Value
+			}
+		}
+	}
+	return got
+}
+
+func TestTelemetry(t *testing.T) {
+	onePath := testutil.GNMIPath(t, "/parent/child/config/one")
+	threePath := testutil.GNMIPath(t, "/parent/child/config/three")
+	config := []*gpb.Update{{
+		Path: onePath,
+		Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "foo"}},
+	}}
+
+	t.Run("spans", func(t *testing.T) {
+		c, exporter, reader := newTelemetryClient(t, &configServer{config: config})
+		ctx := context.Background()
+		child := exampleocpath.Root().Parent().Child()
+
+		if _, err := ygnmi.Lookup(ctx, c, child.One().Config()); err != nil {
+			t.Fatalf("Lookup() returned unexpected error: %v", err)
+		}
+		if _, err := ygnmi.Lookup(ctx, c, child.Three().Config()); err != nil {
+			t.Fatalf("Lookup() returned unexpected error: %v", err)
+		}
+		if _, err := ygnmi.Replace(ctx, c, child.Three().Config(), exampleoc.Child_Three_ONE); err != nil {
+			t.Fatalf("Replace() returned unexpected error: %v", err)
+		}
+		if _, err := ygnmi.Watch(ctx, c, child.One().Config(), func(*ygnmi.Value[string]) error {
+			return errors.New("stop")
+		}).Await(); err == nil {
+			t.Fatalf("Watch() returned no error, want error")
+		}
+		r, err := ygnmi.NewReconciler(c, child.Config())
+		if err != nil {
+			t.Fatalf("NewReconciler() returned unexpected error: %v", err)
+		}
+		r.Start(ctx, func(*ygnmi.Value[*exampleoc.Parent_Child], *ygnmi.Value[*exampleoc.Parent_Child]) error {
+			return nil
+		})
+		if err := r.Await(); !errors.Is(err, io.EOF) {
+			t.Fatalf("Await() returned unexpected error: %v", err)
+		}
+
+		want := []telemetrySpan{{
+			Name: "ygnmi.Lookup",
+			Attrs: map[string]string{
+				"ygnmi.operation": "Lookup",
+				"ygnmi.target":    "dut",
+				"ygnmi.path":      "/parent/child/config/one",
+				"ygnmi.outcome":   "ok",
+			},
+		}, {
+			Name: "ygnmi.Lookup",
+			Attrs: map[string]string{
+				"ygnmi.operation": "Lookup",
+				"ygnmi.target":    "dut",
+				"ygnmi.path":      "/parent/child/config/three",
+				"ygnmi.outcome":   "not_present",
+			},
+		}, {
+			Name: "ygnmi.Set",
+			Attrs: map[string]string{
+				"ygnmi.operation":      "Set",
+				"ygnmi.target":         "dut",
+				"ygnmi.path":           "/parent/child/config/three",
+				"ygnmi.set.operations": "1",
+				"ygnmi.outcome":        "ok",
+			},
+		}, {
+			Name: "ygnmi.Watch",
+			Attrs: map[string]string{
+				"ygnmi.operation": "Watch",
+				"ygnmi.target":    "dut",
+				"ygnmi.path":      "/parent/child/config/one",
+				"ygnmi.outcome":   "error",
+			},
+		}, {
+			Name: "ygnmi.Reconciler",
+			Attrs: map[string]string{
+				"ygnmi.operation": "Reconciler",
+				"ygnmi.target":    "dut",
+				"ygnmi.path":      "/parent/child",
+				"ygnmi.outcome":   "ok",
+			},
+		}}
+		if diff := cmp.Diff(want, exportedSpans(exporter)); diff != "" {
+			t.Errorf("Exported unexpected spans (-want,+got):\n%s", diff)
+		}
+		// One notification for each Lookup of /parent/child/config/one, the Watch and the Reconciler.
+		if diff := cmp.Diff(map[string]int64{"dut": 3}, sums(t, reader, "ygnmi.notifications", "ygnmi.target")); diff != "" {
+			t.Errorf("ygnmi.notifications has unexpected values (-want,+got):\n%s", diff)
+		}
+	})
+
+	t.Run("compliance errors", func(t *testing.T) {
+		c, _, reader := newTelemetryClient(t, &configServer{config: []*gpb.Update{{
+			Path: testutil.GNMIPath(t, "/parent/child/config/unknown"),
+			Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "foo"}},
+		}, {
+			Path: threePath,
+			Val:  &gpb.TypedValue{Value: &gpb.TypedValue_IntVal{IntVal: 1}},
+		}}})
+		if _, err := ygnmi.Lookup(context.Background(), c, exampleocpath.Root().Parent().Child().Config()); err != nil {
+			t.Fatalf("Lookup() returned unexpected error: %v", err)
+		}
+		want := map[string]int64{"path": 1, "type": 1}
+		if diff := cmp.Diff(want, sums(t, reader, "ygnmi.compliance_errors", "ygnmi.compliance.category")); diff != "" {
+			t.Errorf("ygnmi.compliance_errors has unexpected values (-want,+got):\n%s", diff)
+		}
+		var rm metricdata.ResourceMetrics
+		if err := reader.Collect(context.Background(), &rm); err != nil {
+			t.Fatalf("Collect() returned unexpected error: %v", err)
+		}
+		var count uint64
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				if h, ok := m.Data.(metricdata.Histogram[float64]); ok && m.Name == "ygnmi.unmarshal.duration" {
+					for _, dp := range h.DataPoints {
+						count += dp.Count
+					}
+				}
+			}
+		}
+		if count != 1 {
+			t.Errorf("ygnmi.unmarshal.duration got %d records, want 1", count)
+		}
+	})
+}
@@ -45,6 +45,8 @@ type DataPoint struct {
 	RecvTimestamp time.Time
 	// Sync indicates whether the received datapoint was gNMI sync response.
 	Sync bool
+	// redactor masks the value in String if it is sensitive, see WithRedactionRules.
+	redactor *redactor
 }
 
 func (d *DataPoint) String() string {
@@ -58,8 +60,9 @@ func (d *DataPoint) String() string {
 	if err != nil {
 		path = prototext.Format(d.Path)
 	}
-	valStr := fmt.Sprint(d.Value)
-	if jsonietf := d.Value.GetJsonIetfVal(); len(jsonietf) > 0 {
+	val := d.redactor.typedValue(d.Value, d.Path)
+	valStr := fmt.Sprint(val)
+	if jsonietf := val.GetJsonIetfVal(); len(jsonietf) > 0 {
 		valStr = string(jsonietf)
 	}
 	return fmt.Sprintf("%s (timestamp: %v, recvTimestamp: %v, isSync: %v): %s", path, d.Timestamp, d.RecvTimestamp, d.Sync, valStr)
@@ -71,13 +74,15 @@ type TelemetryError struct {
 	Path  *gpb.Path
 	Value *gpb.TypedValue
 	Err   error
+	// redactor masks the value in String if it is sensitive, see WithRedactionRules.
+	redactor *redactor
 }
 
 func (t *TelemetryError) String() string {
 	if t == nil {
 		return ""
 	}
-	return fmt.Sprintf("Unmarshal %v into %v: %s", t.Value, t.Path, t.Err.Error())
+	return fmt.Sprintf("Unmarshal %v into %v: %s", t.redactor.typedValue(t.Value, t.Path), t.Path, t.Err.Error())
 }
 
 // ComplianceErrors contains the compliance errors encountered from an Unmarshal operation.
@@ -147,6 +152,9 @@ func unmarshalAndExtract[T any](data []*DataPoint, q AnyQuery[T], goStruct ygot.
 	ret := &Value[T]{
 		Path: queryPath,
 	}
+	if opts != nil {
+		ret.redactor = opts.log.redactor()
+	}
 	if len(data) == 0 {
 		return ret, nil
 	}
@@ -172,7 +180,9 @@ func unmarshalAndExtract[T any](data []*DataPoint, q AnyQuery[T], goStruct ygot.
 		return ret, nil
 	}
 
+	start := time.Now()
 	unmarshalledData, complianceErrs, err := unmarshal(data, schema.SchemaTree[q.dirName()], goStruct, queryPath, schema, q.isLeaf(), q.isShadowPath(), q.compressInfo(), opts)
+	opts.telemetry.recordUnmarshal(start, complianceErrs)
 	ret.ComplianceErrors = complianceErrs
 	if err != nil {
 		return ret, err
@@ -347,12 +357,13 @@ func unmarshal(data []*DataPoint, structSchema *yang.Entry, structPtr ygot.Valid
 			case len(dp.Path.Elem) == 0 && len(dp.Path.Element) > 0:
 				pathErr = fmt.Errorf("datapoint path uses deprecated and unsupported Element field: %s", prototext.Format(dp.Path))
 			default:
-				pathErr = fmt.Errorf("datapoint path %q (value %v) does not match the query path %q", dpPathStr, dp.Value, queryPathStr)
+				pathErr = fmt.Errorf("datapoint path %q (value %v) does not match the query path %q", dpPathStr, dp.redactor.typedValue(dp.Value, dp.Path), queryPathStr)
 			}
 			pathUnmarshalErrs = append(pathUnmarshalErrs, &TelemetryError{
-				Path:  dp.Path,
-				Value: dp.Value,
-				Err:   pathErr,
+				Path:     dp.Path,
+				Value:    dp.Value,
+				Err:      pathErr,
+				redactor: dp.redactor,
 			})
 			continue
 		}
@@ -376,7 +387,7 @@ func unmarshal(data []*DataPoint, structSchema *yang.Entry, structPtr ygot.Valid
 		// root, we check that the path, including the list key,
 		// corresponds to an actual schema element.
 		if _, _, err := ytypes.GetOrCreateNode(schema.RootSchema(), schema.Root, unmarshalPath, gcopts...); err != nil {
-			pathUnmarshalErrs = append(pathUnmarshalErrs, &TelemetryError{Path: dp.Path, Value: dp.Value, Err: fmt.Errorf("path %q is invalid and cannot be matched to a generated GoStruct field: %v", dpPathStr, err)})
+			pathUnmarshalErrs = append(pathUnmarshalErrs, &TelemetryError{Path: dp.Path, Value: dp.Value, Err: fmt.Errorf("path %q is invalid and cannot be matched to a generated GoStruct field: %v", dpPathStr, err), redactor: dp.redactor})
 			continue
 		}
 		// The structSchema passed in here is assumed to be the unzipped
@@ -410,7 +421,12 @@ func unmarshal(data []*DataPoint, structSchema *yang.Entry, structPtr ygot.Valid
 			if err := ytypes.SetNode(structSchema, structPtr, relPath, dp.Value, sopts...); err == nil {
 				unmarshalledDatapoints = append(unmarshalledDatapoints, dp)
 			} else {
-				typeUnmarshalErrs = append(typeUnmarshalErrs, &TelemetryError{Path: dp.Path, Value: dp.Value, Err: fmt.Errorf("datapoint path %q (value %v) cannot be unmarshalled: %v", dpPathStr, dp.Value, err)})
+				val, cause := dp.redactor.typedValue(dp.Value, dp.Path), err.Error()
+				if val != dp.Value {
+					// The errors of ytypes quote the value, so they are masked along with a sensitive value.
+					cause = redactedText
+				}
+				typeUnmarshalErrs = append(typeUnmarshalErrs, &TelemetryError{Path: dp.Path, Value: dp.Value, Err: fmt.Errorf("datapoint path %q (value %v) cannot be unmarshalled: %s", dpPathStr, val, cause), redactor: dp.redactor})
 			}
 		}
 	}
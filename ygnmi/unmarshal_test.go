@@ -764,7 +764,7 @@ func TestUnmarshal(t *testing.T) {
 				t.Fatalf("unmarshal: got compliance errors, want none: %v", complianceErrs)
 			}
 
-			if diff := cmp.Diff(tt.wantUnmarshalledData, unmarshalledData, protocmp.Transform()); diff != "" {
+			if diff := cmp.Diff(tt.wantUnmarshalledData, unmarshalledData, cmp.AllowUnexported(DataPoint{}), protocmp.Transform()); diff != "" {
 				t.Errorf("unmarshal: successfully unmarshalled datapoints do not match (-want +got):\n%s", diff)
 			}
 			if diff := cmp.Diff(tt.wantStruct, tt.inStruct); diff != "" {
@@ -1008,7 +1008,7 @@ func TestUnmarshal(t *testing.T) {
 			if errs != nil {
 				t.Fatalf("unmarshal: got more than one error: %v", errs)
 			}
-			if diff := cmp.Diff(tt.wantUnmarshalledData, unmarshalledData, protocmp.Transform()); diff != "" {
+			if diff := cmp.Diff(tt.wantUnmarshalledData, unmarshalledData, cmp.AllowUnexported(DataPoint{}), protocmp.Transform()); diff != "" {
 				t.Errorf("unmarshal: successfully unmarshalled datapoints do not match (-want +got):\n%s", diff)
 			}
 
@@ -1061,7 +1061,7 @@ func TestUnmarshal(t *testing.T) {
 			verifyTelemetryError := func(t *testing.T, gotErr, wantErrSubstr *TelemetryError) {
 				t.Helper()
 				// Only do exact verification on the Path and Value fields of the Telemetry errors.
-				if diff := cmp.Diff(wantErrSubstr, gotErr, protocmp.Transform(), cmp.FilterPath(
+				if diff := cmp.Diff(wantErrSubstr, gotErr, protocmp.Transform(), cmp.AllowUnexported(TelemetryError{}), cmp.FilterPath(
 					func(p cmp.Path) bool {
 						return p.String() == "Err"
 					},
@@ -1298,7 +1298,7 @@ func TestBundleDatapoints(t *testing.T) {
 			if gotErr := err != nil; gotErr != tt.wantErr {
 				t.Errorf("Got error: %v, want error: %v", err, tt.wantErr)
 			}
-			if diff := cmp.Diff(got, tt.want, protocmp.Transform()); diff != "" {
+			if diff := cmp.Diff(got, tt.want, cmp.AllowUnexported(DataPoint{}), protocmp.Transform()); diff != "" {
 				t.Errorf("Datapoint groups (-got, +want):\n%s", diff)
 			}
 			if diff := cmp.Diff(gotPrefixes, tt.wantPrefixes); diff != "" {
@@ -0,0 +1,146 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openconfig/ygot/util"
+	"github.com/openconfig/ygot/ygot"
+	"github.com/openconfig/ygot/ytypes"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// SetValidationError is an error found validating the value of a Set operation.
+type SetValidationError struct {
+	// Path is the path of the operation.
+	Path *gpb.Path
+	// Err is the error.
+	Err error
+}
+
+func (e *SetValidationError) String() string {
+	if e == nil {
+		return ""
+	}
+	return fmt.Sprintf("Validate value at %s: %v", pathString(e.Path), e.Err)
+}
+
+// SetValidationErrors contains the errors found validating the values of a SetRequest
+// before sending it, see WithSetValidation.
+// Like ComplianceErrors, the errors are grouped by category.
+type SetValidationErrors struct {
+	// TypeErrors are errors encountered due to a value that can't be converted to the type of the schema.
+	TypeErrors []*SetValidationError
+	// ValidateErrors are errors encountered due to a value that doesn't conform to the restrictions
+	// of the schema, such as patterns, ranges and leafrefs.
+	ValidateErrors []*SetValidationError
+}
+
+func (e *SetValidationErrors) String() string {
+	if e == nil {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("Set Validation Errors by category:")
+	for _, c := range []struct {
+		name string
+		errs []*SetValidationError
+	}{
+		{name: "Type", errs: e.TypeErrors},
+		{name: "Value Restriction", errs: e.ValidateErrors},
+	} {
+		fmt.Fprintf(&b, "\n%s Validation Errors:", c.name)
+		if len(c.errs) == 0 {
+			b.WriteString(" None")
+		}
+		for _, err := range c.errs {
+			b.WriteString("\n\t")
+			b.WriteString(err.String())
+		}
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// Error returns the errors as a string, so that they can be returned by Set.
+func (e *SetValidationErrors) Error() string {
+	return e.String()
+}
+
+// add appends the errors of other to the errors.
+func (e *SetValidationErrors) add(other *SetValidationErrors) {
+	if other == nil {
+		return
+	}
+	e.TypeErrors = append(e.TypeErrors, other.TypeErrors...)
+	e.ValidateErrors = append(e.ValidateErrors, other.ValidateErrors...)
+}
+
+// empty returns whether there are no errors.
+func (e *SetValidationErrors) empty() bool {
+	return len(e.TypeErrors) == 0 && len(e.ValidateErrors) == 0
+}
+
+// validateFn returns a func validating the value of a Set operation at the query.
+func validateFn[T any](q AnyQuery[T], val T) func(*gpb.Path, []ygot.ValidationOption) *SetValidationErrors {
+	return func(path *gpb.Path, opts []ygot.ValidationOption) *SetValidationErrors {
+		return validateSetValue(q, path, val, opts)
+	}
+}
+
+// validateSetValue validates the value of a Set operation at the query against its schema.
+// GoStructs are validated directly, while leaf values are first set in an empty parent GoStruct.
+// Schemaless queries are not validated.
+func validateSetValue[T any](q AnyQuery[T], path *gpb.Path, val T, opts []ygot.ValidationOption) *SetValidationErrors {
+	schema := q.schema()
+	if schema == nil {
+		return nil
+	}
+	structSchema := schema.SchemaTree[q.dirName()]
+	errs := &SetValidationErrors{}
+	newErr := func(err error) *SetValidationError {
+		return &SetValidationError{Path: path, Err: err}
+	}
+
+	var structPtr any = val
+	if q.isLeaf() {
+		tv, err := ygot.EncodeTypedValue(val, gpb.Encoding_JSON_IETF)
+		if err != nil {
+			errs.TypeErrors = append(errs.TypeErrors, newErr(fmt.Errorf("cannot encode value %v: %v", val, err)))
+			return errs
+		}
+		gs := q.goStruct()
+		relPath := util.TrimGNMIPathPrefix(path, util.PathStringToElements(structSchema.Path())[1:])
+		sopts := []ytypes.SetNodeOpt{&ytypes.InitMissingElements{}}
+		if q.isShadowPath() {
+			sopts = append(sopts, &ytypes.PreferShadowPath{})
+		}
+		if err := ytypes.SetNode(structSchema, gs, relPath, tv, sopts...); err != nil {
+			errs.TypeErrors = append(errs.TypeErrors, newErr(fmt.Errorf("value %v cannot be set: %v", val, err)))
+			return errs
+		}
+		structPtr = gs
+	}
+	for _, err := range ytypes.Validate(structSchema, structPtr, opts...) {
+		errs.ValidateErrors = append(errs.ValidateErrors, newErr(err))
+	}
+	if errs.empty() {
+		return nil
+	}
+	return errs
+}
@@ -0,0 +1,138 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ygnmi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/ygnmi/exampleoc"
+	"github.com/openconfig/ygnmi/exampleoc/exampleocpath"
+	"github.com/openconfig/ygnmi/ygnmi"
+	"github.com/openconfig/ygot/ygot"
+)
+
+func TestSetValidation(t *testing.T) {
+	ctx := context.Background()
+	child := exampleocpath.Root().Parent().Child()
+
+	tests := []struct {
+		desc                string
+		op                  func(*ygnmi.Client, ...ygnmi.Option) error
+		wantErr             string
+		wantTypeErrors      int
+		wantValidateErrors  int
+		wantErrWithoutCheck bool
+	}{{
+		desc: "valid container",
+		op: func(c *ygnmi.Client, opts ...ygnmi.Option) error {
+			_, err := ygnmi.Replace(ctx, c, child.Config(), &exampleoc.Parent_Child{
+				One:  ygot.String("foo"),
+				Five: exampleoc.Binary("abcd"),
+			}, opts...)
+			return err
+		},
+	}, {
+		desc: "invalid container",
+		op: func(c *ygnmi.Client, opts ...ygnmi.Option) error {
+			_, err := ygnmi.Replace(ctx, c, child.Config(), &exampleoc.Parent_Child{
+				One:  ygot.String("foo"),
+				Five: exampleoc.Binary("abc"),
+			}, opts...)
+			return err
+		},
+		wantErr:            "Validate value at /parent/child: /root/parent/child/state/five: schema \"five\": length 3 is outside range 4",
+		wantValidateErrors: 1,
+	}, {
+		desc: "valid leaf",
+		op: func(c *ygnmi.Client, opts ...ygnmi.Option) error {
+			_, err := ygnmi.Update(ctx, c, child.Three().Config(), exampleoc.Child_Three_TWO, opts...)
+			return err
+		},
+	}, {
+		desc: "invalid enum",
+		op: func(c *ygnmi.Client, opts ...ygnmi.Option) error {
+			_, err := ygnmi.Update(ctx, c, child.Three().Config(), exampleoc.E_Child_Three(42), opts...)
+			return err
+		},
+		wantErr:             "Validate value at /parent/child/config/three",
+		wantTypeErrors:      1,
+		wantErrWithoutCheck: true,
+	}, {
+		desc: "delete length-constrained leaf",
+		op: func(c *ygnmi.Client, opts ...ygnmi.Option) error {
+			_, err := ygnmi.Delete(ctx, c, child.Five().Config(), opts...)
+			return err
+		},
+	}, {
+		desc: "delete enum leaf",
+		op: func(c *ygnmi.Client, opts ...ygnmi.Option) error {
+			_, err := ygnmi.Delete(ctx, c, child.Three().Config(), opts...)
+			return err
+		},
+	}, {
+		desc: "batch",
+		op: func(c *ygnmi.Client, opts ...ygnmi.Option) error {
+			sb := &ygnmi.SetBatch{}
+			ygnmi.BatchReplace(sb, child.Config(), &exampleoc.Parent_Child{Five: exampleoc.Binary("abc")})
+			ygnmi.BatchUpdate(sb, child.One().Config(), "foo")
+			ygnmi.BatchUnionReplace(sb, child.Config(), &exampleoc.Parent_Child{Five: exampleoc.Binary("ab")})
+			ygnmi.BatchDelete(sb, child.Four().Config())
+			_, err := sb.Set(ctx, c, opts...)
+			return err
+		},
+		wantErr:            "Value Restriction Validation Errors:",
+		wantValidateErrors: 2,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			srv := &setServer{}
+			c := newServerClient(t, srv)
+
+			if err := tt.op(c); (err != nil) != tt.wantErrWithoutCheck {
+				t.Fatalf("operation without validation returned error %v, want error %v", err, tt.wantErrWithoutCheck)
+			}
+			srv.mu.Lock()
+			srv.requests = nil
+			srv.mu.Unlock()
+
+			err := tt.op(c, ygnmi.WithSetValidation())
+			if diff := errdiff.Substring(err, tt.wantErr); diff != "" {
+				t.Fatalf("operation with validation returned unexpected diff: %s", diff)
+			}
+			if tt.wantErr == "" {
+				if len(srv.Requests()) != 1 {
+					t.Errorf("Number of SetRequests got %d, want 1", len(srv.Requests()))
+				}
+				return
+			}
+			if len(srv.Requests()) != 0 {
+				t.Errorf("Number of SetRequests got %d, want 0", len(srv.Requests()))
+			}
+			var verrs *ygnmi.SetValidationErrors
+			if !errors.As(err, &verrs) {
+				t.Fatalf("operation with validation returned error %T, want *SetValidationErrors", err)
+			}
+			if got := len(verrs.TypeErrors); got != tt.wantTypeErrors {
+				t.Errorf("Number of TypeErrors got %d, want %d:\n%v", got, tt.wantTypeErrors, verrs)
+			}
+			if got := len(verrs.ValidateErrors); got != tt.wantValidateErrors {
+				t.Errorf("Number of ValidateErrors got %d, want %d:\n%v", got, tt.wantValidateErrors, verrs)
+			}
+		})
+	}
+}
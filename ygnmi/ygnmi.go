@@ -19,18 +19,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"iter"
+	"maps"
 	"reflect"
+	"slices"
 	"time"
 
-	"github.com/openconfig/ygnmi/internal/logutil"
 	"github.com/openconfig/ygot/util"
 	"github.com/openconfig/ygot/ygot"
 	"github.com/openconfig/ygot/ytypes"
-	"google.golang.org/protobuf/encoding/prototext"
 	"google.golang.org/protobuf/proto"
 
 	log "github.com/golang/glog"
 	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	gnmiextpb "github.com/openconfig/gnmi/proto/gnmi_ext"
 )
 
 // UntypedQuery is a generic gNMI query for wildcard or non-wildcard state or config paths.
@@ -112,6 +115,8 @@ type Value[T any] struct {
 	RecvTimestamp time.Time
 	// ComplianceErrors contains the compliance errors encountered from an Unmarshal operation.
 	ComplianceErrors *ComplianceErrors
+	// redactor masks the value in String if it is sensitive, see WithRedactionRules.
+	redactor *redactor
 }
 
 // SetVal sets the value and marks it present and returns the receiver.
@@ -139,7 +144,7 @@ func (v *Value[T]) String() string {
 	}
 	val := "(not present)"
 	if v.present {
-		val = fmt.Sprintf("%+v", v.val)
+		val = fmt.Sprintf("%+v", v.redactor.value(v.Path, v.val))
 	}
 	return fmt.Sprintf("path: %s\nvalue: %s", path, val)
 }
@@ -149,6 +154,15 @@ type Client struct {
 	gnmiC           gpb.GNMIClient
 	target          string
 	requestLogLevel log.Level
+	subMgr          *subscriptionManager
+	encodings       *encodingNegotiator
+	// unaryInterceptors and streamInterceptors are the interceptors of the RPCs, see WithInterceptors.
+	unaryInterceptors  []UnaryInterceptor
+	streamInterceptors []StreamInterceptor
+	telemetry          *telemetry
+	log                *logger
+	// redaction masks the sensitive values in the logs and the values of the client, see WithRedactionRules.
+	redaction *redactor
 }
 
 // String returns a string representation of Client. This output is unstable.
@@ -176,12 +190,32 @@ func WithRequestLogLevel(l log.Level) ClientOption {
 	}
 }
 
+// WithSubscriptionSharing enables sharing STREAM subscriptions between concurrent
+// watchers (Watch, WatchAll, Collect, CollectAll, Await, Stream, StreamAll and Reconciler) using the client.
+// Watchers whose SubscribeRequests are identical share a single upstream subscription,
+// whose notifications are fanned out to all of them. The upstream subscription is
+// closed when the last watcher using it finishes, or when it fails.
+// Only identical requests are shared: watchers of overlapping paths, for example a container
+// and one of its leaves, or of the same path with different options, use separate subscriptions.
+// The upstream subscription is opened without blocking the other subscriptions of the client,
+// and watchers of a subscription that is still being opened wait for it until their context is done.
+// A watcher joining an existing subscription first receives the latest values received
+// on it, followed by a sync response if the subscription has already synced.
+// Notifications are delivered to the watchers in turn, so a slow watcher delays the others.
+func WithSubscriptionSharing() ClientOption {
+	return func(c *Client) error {
+		c.subMgr = newSubscriptionManager()
+		return nil
+	}
+}
+
 // NewClient creates a new client with specified options.
 func NewClient(c gpb.GNMIClient, opts ...ClientOption) (*Client, error) {
 	yc := &Client{
 		gnmiC:           c,
 		requestLogLevel: 1,
 	}
+	yc.log = &logger{client: yc}
 	for _, opt := range opts {
 		if err := opt(yc); err != nil {
 			return nil, err
@@ -254,12 +288,21 @@ type opt struct {
 	useGet             bool
 	mode               gpb.SubscriptionMode
 	encoding           gpb.Encoding
+	encodingSet        bool
 	preferProto        bool
 	setFallback        bool
 	sampleInterval     uint64
 	datapointValidator ValidateFn
 	appendModuleName   bool
 	ft                 FunctionalTranslator
+	resubscribe        *ResubscribePolicy
+	cache              *Cache
+	history            *gnmiextpb.History
+	validateSet        bool
+	validationOpts     []ygot.ValidationOption
+	split              *SetSplitPolicy
+	telemetry          *telemetry
+	log                *logger
 }
 
 // resolveOpts applies all the options and returns a struct containing the result.
@@ -274,6 +317,14 @@ func resolveOpts(opts []Option) *opt {
 	return o
 }
 
+// resolveClientOpts applies all the options of a call made with the client.
+func resolveClientOpts(c *Client, opts []Option) *opt {
+	o := resolveOpts(opts)
+	o.telemetry = c.telemetry
+	o.log = c.log
+	return o
+}
+
 // WithUseGet creates an option to use gnmi.Get instead of gnmi.Subscribe.
 // This can only be used on Get, GetAll, Lookup, and LookupAll.
 func WithUseGet() Option {
@@ -284,7 +335,7 @@ func WithUseGet() Option {
 
 // WithSubscriptionMode creates an option to use input instead of the default (TARGET_DEFINED).
 // This option is only relevant for Watch, WatchAll, Collect, CollectAll, Await which are STREAM subscriptions.
-// The mode applies to all paths in the Subcription.
+// The mode applies to all paths in the Subcription, unless overridden by SubscriptionParams.
 func WithSubscriptionMode(mode gpb.SubscriptionMode) Option {
 	return func(o *opt) {
 		o.mode = mode
@@ -294,7 +345,7 @@ func WithSubscriptionMode(mode gpb.SubscriptionMode) Option {
 // WithSampleInterval creates an option to set the sample interval in the Subcribe request.
 // NOTE: The subscription mode must be set to SAMPLE for this to have an effect.
 // This option is only relevant for Watch, WatchAll, Collect, CollectAll, Await which are STREAM subscriptions.
-// The mode applies to all paths in the Subcription.
+// The mode applies to all paths in the Subcription, unless overridden by SubscriptionParams.
 func WithSampleInterval(d time.Duration) Option {
 	return func(o *opt) {
 		o.sampleInterval = uint64(d.Nanoseconds())
@@ -303,20 +354,76 @@ func WithSampleInterval(d time.Duration) Option {
 
 // WithEncoding creates an option to set the Encoding for all Subscribe or Get requests.
 // The default encoding is PROTO. This does not apply when using WithUseGet, whith uses JSON_IETF encoding.
+// See WithEncodingNegotiation for choosing the encoding from the encodings supported by the target.
 func WithEncoding(enc gpb.Encoding) Option {
 	return func(o *opt) {
 		o.encoding = enc
+		o.encodingSet = true
 	}
 }
 
 // WithSetPreferProtoEncoding creates an option that prefers encoding SetRequest using proto encoding.
 // This option is only relevant for Update and Replace.
+// See WithEncodingNegotiation for when the preference is honored with negotiated encodings.
 func WithSetPreferProtoEncoding() Option {
 	return func(o *opt) {
 		o.preferProto = true
 	}
 }
 
+// WithSetValidation creates an option to validate the values of Update, Replace, UnionReplace
+// and the corresponding batch operations against their schema before sending the SetRequest.
+// GoStruct values are validated with the options, leaf values are validated within an otherwise empty parent GoStruct.
+// If any value is invalid, the SetRequest is not sent and a *SetValidationErrors is returned.
+// Leafrefs pointing outside of the value can't be resolved, use &ytypes.LeafrefOptions{IgnoreMissingData: true} to skip them.
+// This option is only relevant for Update, Replace, UnionReplace and SetBatch.
+func WithSetValidation(opts ...ygot.ValidationOption) Option {
+	return func(o *opt) {
+		o.validateSet = true
+		o.validationOpts = opts
+	}
+}
+
+// SetSplitPolicy configures how large SetRequests are split, see WithSetSplitting.
+// A zero limit means there is no limit.
+type SetSplitPolicy struct {
+	// MaxValueBytes is the maximum size of the JSON value of a single Update or Replace entry.
+	// Larger JSON objects are split into several entries at the same path.
+	MaxValueBytes int
+	// MaxRequestBytes is the maximum size of the encoded SetRequest.
+	// Larger requests are split into several SetRequests sent sequentially.
+	MaxRequestBytes int
+}
+
+// WithSetSplitting creates an option to split SetRequests with large payloads according to the policy.
+//
+// Values larger than MaxValueBytes are split into several entries at the same path, which each contain
+// a subset of the members of the JSON object. Containers are split by member, recursively,
+// and lists by whole entries. An Update is split into several Updates.
+// A Replace is split into a Replace with the first subset followed by Updates with the others,
+// in the same SetRequest: since the target processes replaces before updates, the result is the same.
+//
+// Requests larger than MaxRequestBytes are split into several SetRequests that are sent in order,
+// each one after the previous one succeeded. The first SetRequest contains all deletes, replaces
+// and union replaces, followed by as many updates as fit; the remaining updates are sent in the
+// following SetRequests, in their original order. The requests are not applied atomically:
+// if one fails, the changes of the previous ones are kept.
+//
+// The following splits would change the semantics of the request, so they are refused with an error:
+//   - a Replace, Delete or Union Replace spanning several SetRequests, since the target would hold
+//     a partially replaced config in between. The request fails if these operations don't fit in one SetRequest.
+//   - splitting the value of a Union Replace, which replaces the union of the config with the value.
+//   - splitting a leaf-list or a single list entry, which are replaced as a whole, or a scalar value.
+//   - splitting a value that isn't JSON or JSON_IETF encoded.
+//
+// SetWithCommit fails if the request needs to be split into several SetRequests.
+// This option is only relevant for Update, Replace, Delete and SetBatch.
+func WithSetSplitting(policy *SetSplitPolicy) Option {
+	return func(o *opt) {
+		o.split = policy
+	}
+}
+
 // WithSetFallbackEncoding creates an option that fallback to encoding SetRequests with JSON or an Any proto.
 // Fallback encoding is if the parameter is neither GoStruct nor a leaf for non OpenConfig paths.
 // This option is only relevant for Update and Replace.
@@ -388,18 +495,126 @@ func WithFT(ft FunctionalTranslator) Option {
 	}
 }
 
-// Lookup fetches the value of a SingletonQuery with a ONCE subscription.
+// ResubscribePolicy configures how a failed STREAM subscription is reopened.
+// The zero value retries indefinitely, starting with a 1s backoff that doubles
+// on each consecutive failure up to a maximum of 1m.
+type ResubscribePolicy struct {
+	// InitialBackoff is the delay before the first resubscribe attempt.
+	InitialBackoff time.Duration
+	// MaxBackoff is the maximum delay between resubscribe attempts.
+	MaxBackoff time.Duration
+	// Multiplier is the factor the backoff is multiplied by after each failed attempt.
+	Multiplier float64
+	// MaxAttempts is the number of consecutive failed attempts after which the
+	// subscription is given up. Zero means there is no limit.
+	MaxAttempts int
+	// Retryable reports whether the subscription should be reopened after the error.
+	// If nil, all errors are retried.
+	Retryable func(error) bool
+}
+
+func (p *ResubscribePolicy) initialBackoff() time.Duration {
+	if p.InitialBackoff <= 0 {
+		return time.Second
+	}
+	return p.InitialBackoff
+}
+
+func (p *ResubscribePolicy) nextBackoff(d time.Duration) time.Duration {
+	mult := p.Multiplier
+	if mult < 1 {
+		mult = 2
+	}
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = time.Minute
+	}
+	d = time.Duration(float64(d) * mult)
+	if d > maxBackoff {
+		return maxBackoff
+	}
+	return d
+}
+
+func (p *ResubscribePolicy) retryable(err error) bool {
+	return p.Retryable == nil || p.Retryable(err)
+}
+
+// WithResubscribe creates an option that reopens the subscription with exponential backoff
+// when the stream fails, for example because of a gRPC stream reset.
+// The values already received are kept, and are re-synced with the initial sync of the new subscription:
+// values that are not present after the new sync are deleted.
+// The resubscribe attempts are reset once the new subscription has synced.
+// This option is only relevant for Watch, WatchAll, Collect, CollectAll, Await, Stream, StreamAll, Reconciler
+// and NewCache which are STREAM subscriptions.
+func WithResubscribe(policy *ResubscribePolicy) Option {
+	return func(o *opt) {
+		o.resubscribe = policy
+	}
+}
+
+// WithCache creates an option to answer Lookup, LookupAll, Get and GetAll from the cache
+// instead of creating a ONCE subscription. The call waits for the initial sync of the cache,
+// and fails if the subscription of the cache has failed.
+func WithCache(cache *Cache) Option {
+	return func(o *opt) {
+		o.cache = cache
+	}
+}
+
+// WithHistorySnapshot creates an option to request the values as they were at time t,
+// using the gNMI history extension. The target must support the extension.
+// This option is only relevant for Lookup, LookupAll, Get and GetAll,
+// and can't be used with WithUseGet or WithCache.
+func WithHistorySnapshot(t time.Time) Option {
+	return func(o *opt) {
+		o.history = &gnmiextpb.History{
+			Request: &gnmiextpb.History_SnapshotTime{SnapshotTime: t.UnixNano()},
+		}
+	}
+}
+
+// WithHistoryRange creates an option to request the values that changed between start and end,
+// using the gNMI history extension. The target must support the extension.
+// The target sends the historical values followed by a sync, and then closes the subscription,
+// so Collect and CollectAll return the collected values without waiting for the context to be cancelled.
+// This option is only relevant for Collect and CollectAll, and can't be used with WithUseGet.
+func WithHistoryRange(start, end time.Time) Option {
+	return func(o *opt) {
+		o.history = &gnmiextpb.History{
+			Request: &gnmiextpb.History_Range{Range: &gnmiextpb.TimeRange{
+				Start: start.UnixNano(),
+				End:   end.UnixNano(),
+			}},
+		}
+	}
+}
+
+// Lookup fetches the value of a SingletonQuery with a ONCE subscription,
+// or from a Cache if WithCache is used.
 func Lookup[T any](ctx context.Context, c *Client, q SingletonQuery[T], opts ...Option) (*Value[T], error) {
-	resolvedOpts := resolveOpts(opts)
-	sub, err := subscribe[T](ctx, c, q, gpb.SubscriptionList_ONCE, resolvedOpts)
-	if err != nil {
-		return nil, fmt.Errorf("failed to subscribe to path: %w", err)
+	ctx, span := c.telemetry.start(ctx, "Lookup", q)
+	val, err := lookup(ctx, c, q, opts)
+	outcome := outcomeOf(err)
+	if err == nil && !val.IsPresent() {
+		outcome = outcomeAbsent
 	}
-	data, err := receiveAll(sub, false, q, resolvedOpts)
+	endSpan(span, outcome, err)
+	return val, err
+}
+
+func lookup[T any](ctx context.Context, c *Client, q SingletonQuery[T], opts []Option) (*Value[T], error) {
+	resolvedOpts := resolveClientOpts(c, opts)
+	data, err := lookupData[T](ctx, c, q, resolvedOpts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to receive to data: %w", err)
+		return nil, err
 	}
-	val, err := unmarshalAndExtract[T](data, q, q.goStruct(), resolvedOpts)
+	return unmarshalValue(data, q, resolvedOpts)
+}
+
+// unmarshalValue unmarshals the datapoints received for a SingletonQuery.
+func unmarshalValue[T any](data []*DataPoint, q SingletonQuery[T], o *opt) (*Value[T], error) {
+	val, err := unmarshalAndExtract[T](data, q, q.goStruct(), o)
 	if err != nil {
 		return val, fmt.Errorf("failed to unmarshal data: %w", err)
 	}
@@ -407,11 +622,35 @@ func Lookup[T any](ctx context.Context, c *Client, q SingletonQuery[T], opts ...
 		if q.isLeaf() {
 			return val, fmt.Errorf("noncompliant data encountered while unmarshalling leaf: %v", val.ComplianceErrors)
 		}
-		log.V(0).Infof("noncompliant data encountered while unmarshalling: %v", val.ComplianceErrors)
+		o.log.info(context.Background(), 0, "noncompliant data encountered while unmarshalling", "path", pathString(val.Path), "errors", val.ComplianceErrors.String())
 	}
 	return val, nil
 }
 
+// lookupData fetches the datapoints of a query with a ONCE subscription,
+// or from the cache if one is set.
+func lookupData[T any](ctx context.Context, c *Client, q AnyQuery[T], o *opt) ([]*DataPoint, error) {
+	if o.cache != nil {
+		if o.history != nil {
+			return nil, fmt.Errorf("history requests can't be served from a cache")
+		}
+		data, err := o.cache.datapoints(ctx, q)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lookup data in cache: %w", err)
+		}
+		return data, nil
+	}
+	sub, err := subscribe[T](ctx, c, q, gpb.SubscriptionList_ONCE, o)
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to path: %w", err)
+	}
+	data, err := receiveAll(ctx, sub, false, q, o)
+	if err != nil {
+		return nil, fmt.Errorf("failed to receive to data: %w", err)
+	}
+	return data, nil
+}
+
 var (
 	// ErrNotPresent is returned by Get when there are no a values at a path.
 	ErrNotPresent = fmt.Errorf("value not present")
@@ -459,50 +698,60 @@ func (w *Watcher[T]) Await() (*Value[T], error) {
 // Calling Await on the returned Watcher waits for the subscription to complete.
 // It returns the last observed value and a boolean that indicates whether that value satisfies the predicate.
 func Watch[T any](ctx context.Context, c *Client, q SingletonQuery[T], pred func(*Value[T]) error, opts ...Option) *Watcher[T] {
+	ctx, span := c.telemetry.start(ctx, "Watch", q)
 	var cancel context.CancelFunc
 	ctx, cancel = context.WithCancel(ctx)
 	w := &Watcher[T]{
 		errCh: make(chan error, 1),
 	}
 
-	resolvedOpts := resolveOpts(opts)
+	resolvedOpts := resolveClientOpts(c, opts)
 	sub, err := subscribe[T](ctx, c, q, gpb.SubscriptionList_STREAM, resolvedOpts)
 	if err != nil {
 		cancel()
+		endSpan(span, outcomeError, err)
 		w.errCh <- err
 		return w
 	}
 
-	dataCh, errCh := receiveStream[T](ctx, sub, q, resolvedOpts)
 	go func() {
 		defer cancel()
-		// Create an intially empty GoStruct, into which all received datapoints will be unmarshalled.
-		gs := q.goStruct()
-		for {
-			select {
-			case <-ctx.Done():
-				w.errCh <- ctx.Err()
-				return
-			case data := <-dataCh:
-				val, err := unmarshalAndExtract[T](data, q, gs, resolvedOpts)
-				if err != nil {
-					w.errCh <- err
-					return
-				}
-				w.lastVal = val
-				if err := pred(val); err == nil || !errors.Is(err, Continue) {
-					w.errCh <- err
-					return
-				}
-			case err := <-errCh:
-				w.errCh <- err
-				return
-			}
-		}
+		err := w.receive(ctx, c, sub, q, pred, resolvedOpts)
+		endSpan(span, outcomeOf(err), err)
+		w.errCh <- err
 	}()
 	return w
 }
 
+// receive evaluates the predicate with the values received on the subscription
+// until the predicate stops the watch, and returns the error the watch stopped with.
+func (w *Watcher[T]) receive(ctx context.Context, c *Client, sub gpb.GNMI_SubscribeClient, q AnyQuery[T], pred func(*Value[T]) error, o *opt) error {
+	dataCh, errCh := receiveStream[T](ctx, c, sub, q, o)
+	// Create an intially empty GoStruct, into which all received datapoints will be unmarshalled.
+	gs := q.goStruct()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case data, ok := <-dataCh:
+			if !ok {
+				// The stream is closed once the context is done.
+				return ctx.Err()
+			}
+			val, err := unmarshalAndExtract[T](data, q, gs, o)
+			if err != nil {
+				return err
+			}
+			w.lastVal = val
+			if err := pred(val); err == nil || !errors.Is(err, Continue) {
+				return err
+			}
+		case err := <-errCh:
+			return err
+		}
+	}
+}
+
 // Await observes values at Query with a STREAM subscription,
 // blocking until a value that is deep equal to the specified val is received
 // or the context is cancelled. To wait for a generic predicate, or to make a
@@ -521,20 +770,26 @@ func Await[T any](ctx context.Context, c *Client, q SingletonQuery[T], val T, op
 type Collector[T any] struct {
 	w    *Watcher[T]
 	data []*Value[T]
+	// historical is true if the collection is of a history range, which the target ends.
+	historical bool
 }
 
 // Await waits for the collection to finish and returns all received values.
 // When Await returns the watcher is closed, and Await may not be called again.
-// Note: the func blocks until the context is cancelled.
+// Note: the func blocks until the context is cancelled, unless WithHistoryRange is used.
 func (c *Collector[T]) Await() ([]*Value[T], error) {
 	_, err := c.w.Await()
+	if c.historical && errors.Is(err, io.EOF) {
+		// The target closes the subscription once the history range has been sent.
+		return c.data, nil
+	}
 	return c.data, err
 }
 
 // Collect starts an asynchronous collection of the values at the query with a STREAM subscription.
 // Calling Await on the return Collection waits until the context is cancelled and returns the collected values.
 func Collect[T any](ctx context.Context, c *Client, q SingletonQuery[T], opts ...Option) *Collector[T] {
-	collect := &Collector[T]{}
+	collect := &Collector[T]{historical: resolveOpts(opts).history.GetRange() != nil}
 	collect.w = Watch(ctx, c, q, func(v *Value[T]) error {
 		if !q.isLeaf() {
 			// https://go.googlesource.com/proposal/+/refs/heads/master/design/43651-type-parameters.md#why-not-permit-type-assertions-on-values-whose-type-is-a-type-parameter
@@ -550,18 +805,32 @@ func Collect[T any](ctx context.Context, c *Client, q SingletonQuery[T], opts ..
 	return collect
 }
 
-// LookupAll fetches the values of a WildcardQuery with a ONCE subscription.
+// LookupAll fetches the values of a WildcardQuery with a ONCE subscription,
+// or from a Cache if WithCache is used.
 // It returns an empty list if no values are present at the path.
 func LookupAll[T any](ctx context.Context, c *Client, q WildcardQuery[T], opts ...Option) ([]*Value[T], error) {
-	resolvedOpts := resolveOpts(opts)
-	sub, err := subscribe[T](ctx, c, q, gpb.SubscriptionList_ONCE, resolvedOpts)
-	if err != nil {
-		return nil, fmt.Errorf("failed to subscribe to path: %w", err)
+	ctx, span := c.telemetry.start(ctx, "LookupAll", q)
+	vals, err := lookupAll(ctx, c, q, opts)
+	outcome := outcomeOf(err)
+	if err == nil && len(vals) == 0 {
+		outcome = outcomeAbsent
 	}
-	data, err := receiveAll(sub, false, q, resolvedOpts)
+	endSpan(span, outcome, err)
+	return vals, err
+}
+
+func lookupAll[T any](ctx context.Context, c *Client, q WildcardQuery[T], opts []Option) ([]*Value[T], error) {
+	resolvedOpts := resolveClientOpts(c, opts)
+	data, err := lookupData[T](ctx, c, q, resolvedOpts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to receive to data: %w", err)
+		return nil, err
 	}
+	return unmarshalValues(data, q, resolvedOpts)
+}
+
+// unmarshalValues unmarshals the datapoints received for a WildcardQuery,
+// skipping noncompliant leaf values.
+func unmarshalValues[T any](data []*DataPoint, q WildcardQuery[T], o *opt) ([]*Value[T], error) {
 	p, err := resolvePath(q.PathStruct())
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve path: %w", err)
@@ -574,12 +843,12 @@ func LookupAll[T any](ctx context.Context, c *Client, q WildcardQuery[T], opts .
 	var vals []*Value[T]
 	for _, prefix := range sortedPrefixes {
 		goStruct := q.goStruct()
-		v, err := unmarshalAndExtract[T](datapointGroups[prefix], q, goStruct, resolvedOpts)
+		v, err := unmarshalAndExtract[T](datapointGroups[prefix], q, goStruct, o)
 		if err != nil {
 			return nil, fmt.Errorf("failed to unmarshal data: %w", err)
 		}
 		if v.ComplianceErrors != nil {
-			log.V(0).Infof("noncompliant data encountered while unmarshalling: %v", v.ComplianceErrors)
+			o.log.info(context.Background(), 0, "noncompliant data encountered while unmarshalling", "path", pathString(v.Path), "errors", v.ComplianceErrors.String())
 			if q.isLeaf() {
 				continue
 			}
@@ -615,6 +884,7 @@ func GetAll[T any](ctx context.Context, c *Client, q WildcardQuery[T], opts ...O
 // Calling Await on the returned Watcher waits for the subscription to complete.
 // It returns the last observed value and a boolean that indicates whether that value satisfies the predicate.
 func WatchAll[T any](ctx context.Context, c *Client, q WildcardQuery[T], pred func(*Value[T]) error, opts ...Option) *Watcher[T] {
+	ctx, span := c.telemetry.start(ctx, "WatchAll", q)
 	var cancel context.CancelFunc
 	ctx, cancel = context.WithCancel(ctx)
 	w := &Watcher[T]{
@@ -623,64 +893,73 @@ func WatchAll[T any](ctx context.Context, c *Client, q WildcardQuery[T], pred fu
 	path, err := resolvePath(q.PathStruct())
 	if err != nil {
 		cancel()
+		endSpan(span, outcomeError, err)
 		w.errCh <- err
 		return w
 	}
-	resolvedOpts := resolveOpts(opts)
+	resolvedOpts := resolveClientOpts(c, opts)
 	sub, err := subscribe[T](ctx, c, q, gpb.SubscriptionList_STREAM, resolvedOpts)
 	if err != nil {
 		cancel()
+		endSpan(span, outcomeError, err)
 		w.errCh <- err
 		return w
 	}
 
-	dataCh, errCh := receiveStream[T](ctx, sub, q, resolvedOpts)
 	go func() {
 		defer cancel()
-		// Create a map intially empty GoStruct, into which all received datapoints will be unmarshalled based on their path prefixes.
-		structs := map[string]ygot.ValidatedGoStruct{}
-		for {
-			select {
-			case <-ctx.Done():
-				w.errCh <- ctx.Err()
-				return
-			case data := <-dataCh:
-				datapointGroups, sortedPrefixes, err := bundleDatapoints(data, len(path.Elem))
+		err := w.receiveAll(ctx, c, sub, q, path, pred, resolvedOpts)
+		endSpan(span, outcomeOf(err), err)
+		w.errCh <- err
+	}()
+	return w
+}
+
+// receiveAll evaluates the predicate with the values received on the wildcard subscription
+// until the predicate stops the watch, and returns the error the watch stopped with.
+func (w *Watcher[T]) receiveAll(ctx context.Context, c *Client, sub gpb.GNMI_SubscribeClient, q AnyQuery[T], path *gpb.Path, pred func(*Value[T]) error, o *opt) error {
+	dataCh, errCh := receiveStream[T](ctx, c, sub, q, o)
+	// Create a map intially empty GoStruct, into which all received datapoints will be unmarshalled based on their path prefixes.
+	structs := map[string]ygot.ValidatedGoStruct{}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case data, ok := <-dataCh:
+			if !ok {
+				// The stream is closed once the context is done.
+				return ctx.Err()
+			}
+			datapointGroups, sortedPrefixes, err := bundleDatapoints(data, len(path.Elem))
+			if err != nil {
+				return err
+			}
+			for _, pre := range sortedPrefixes {
+				if len(datapointGroups[pre]) == 0 {
+					continue
+				}
+				if _, ok := structs[pre]; !ok {
+					structs[pre] = q.goStruct()
+				}
+				val, err := unmarshalAndExtract[T](datapointGroups[pre], q, structs[pre], o)
 				if err != nil {
-					w.errCh <- err
-					return
+					return err
 				}
-				for _, pre := range sortedPrefixes {
-					if len(datapointGroups[pre]) == 0 {
-						continue
-					}
-					if _, ok := structs[pre]; !ok {
-						structs[pre] = q.goStruct()
-					}
-					val, err := unmarshalAndExtract[T](datapointGroups[pre], q, structs[pre], resolvedOpts)
-					if err != nil {
-						w.errCh <- err
-						return
-					}
-					w.lastVal = val
-					if err := pred(val); err == nil || !errors.Is(err, Continue) {
-						w.errCh <- err
-						return
-					}
+				w.lastVal = val
+				if err := pred(val); err == nil || !errors.Is(err, Continue) {
+					return err
 				}
-			case err := <-errCh:
-				w.errCh <- err
-				return
 			}
+		case err := <-errCh:
+			return err
 		}
-	}()
-	return w
+	}
 }
 
 // CollectAll starts an asynchronous collection of the values at the query with a STREAM subscription.
 // Calling Await on the return Collection waits until the context is cancelled to elapse and returns the collected values.
 func CollectAll[T any](ctx context.Context, c *Client, q WildcardQuery[T], opts ...Option) *Collector[T] {
-	collect := &Collector[T]{}
+	collect := &Collector[T]{historical: resolveOpts(opts).history.GetRange() != nil}
 	collect.w = WatchAll(ctx, c, q, func(v *Value[T]) error {
 		if !q.isLeaf() {
 			// https://go.googlesource.com/proposal/+/refs/heads/master/design/43651-type-parameters.md#why-not-permit-type-assertions-on-values-whose-type-is-a-type-parameter
@@ -696,12 +975,98 @@ func CollectAll[T any](ctx context.Context, c *Client, q WildcardQuery[T], opts
 	return collect
 }
 
+// Stream starts a STREAM subscription and returns an iterator over the observed values.
+// The subscription is closed when the iteration stops, either by breaking out of the loop
+// or when an error is yielded. Errors, including the context being cancelled, are yielded
+// with a nil value and end the iteration.
+// Values are unmarshalled the same way as for Watch: for non-leaf queries, all values share
+// a GoStruct which is updated in place, so use ygot.DeepCopy to retain a value past an iteration.
+func Stream[T any](ctx context.Context, c *Client, q SingletonQuery[T], opts ...Option) iter.Seq2[*Value[T], error] {
+	return func(yield func(*Value[T], error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		resolvedOpts := resolveClientOpts(c, opts)
+		sub, err := subscribe[T](ctx, c, q, gpb.SubscriptionList_STREAM, resolvedOpts)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		w := &Watcher[T]{}
+		if err := w.receive(ctx, c, sub, q, yieldPredicate(yield), resolvedOpts); !errors.Is(err, errIterationStopped) {
+			yield(nil, err)
+		}
+	}
+}
+
+// StreamAll starts a STREAM subscription and returns an iterator over the observed values.
+// See Stream for the details of the iteration.
+func StreamAll[T any](ctx context.Context, c *Client, q WildcardQuery[T], opts ...Option) iter.Seq2[*Value[T], error] {
+	return func(yield func(*Value[T], error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		path, err := resolvePath(q.PathStruct())
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		resolvedOpts := resolveClientOpts(c, opts)
+		sub, err := subscribe[T](ctx, c, q, gpb.SubscriptionList_STREAM, resolvedOpts)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		w := &Watcher[T]{}
+		if err := w.receiveAll(ctx, c, sub, q, path, yieldPredicate(yield), resolvedOpts); !errors.Is(err, errIterationStopped) {
+			yield(nil, err)
+		}
+	}
+}
+
+// errIterationStopped is returned by the predicate of a Stream or StreamAll when the iteration stops.
+var errIterationStopped = errors.New("iteration stopped")
+
+// yieldPredicate returns a watch predicate that yields the values to an iterator,
+// until the iteration stops.
+func yieldPredicate[T any](yield func(*Value[T], error) bool) func(*Value[T]) error {
+	return func(v *Value[T]) error {
+		if !yield(v, nil) {
+			return errIterationStopped
+		}
+		return Continue
+	}
+}
+
 // Result is the result of a Set request.
 type Result struct {
 	// RawResponse is the raw gNMI response received from the server.
 	RawResponse *gpb.SetResponse
 	// Timestamp is the timestamp from the SetResponse as a native Go time struct.
 	Timestamp time.Time
+	// Operations are the results of the operations of the request, in the order they were added.
+	Operations []*OperationResult
+	// Duration is how long the request took, as measured by the client.
+	// If the request was split with WithSetSplitting, it includes all the SetRequests.
+	Duration time.Duration
+	// RolledBack is true if SetWithRollback sent a SetRequest to restore the snapshot of the config.
+	RolledBack bool
+	// RollbackErr is the error of the SetRequest restoring the snapshot, nil if the rollback succeeded.
+	RollbackErr error
+}
+
+// OperationResult is the result of a single operation of a Set request.
+type OperationResult struct {
+	// Path is the path of the operation.
+	Path *gpb.Path
+	// Op is the kind of the operation: update, replace, union_replace or delete.
+	Op gpb.UpdateResult_Operation
+	// Query is the query the operation was added with, or nil if the operation
+	// was built from a gNMI path, for example by Plan or LoadSetBatch.
+	Query UntypedQuery
+	// Response is the UpdateResult returned by the target for the operation,
+	// or nil if the target didn't return one.
+	Response *gpb.UpdateResult
 }
 
 func responseToResult(resp *gpb.SetResponse) *Result {
@@ -711,32 +1076,210 @@ func responseToResult(resp *gpb.SetResponse) *Result {
 	}
 }
 
+// responseUpdates returns the UpdateResults of the response, with their paths joined with the prefix.
+func responseUpdates(resp *gpb.SetResponse) []*gpb.UpdateResult {
+	var updates []*gpb.UpdateResult
+	for _, u := range resp.GetResponse() {
+		if path, err := util.JoinPaths(resp.GetPrefix(), u.GetPath()); err == nil {
+			u = proto.Clone(u).(*gpb.UpdateResult)
+			u.Path = path
+		}
+		updates = append(updates, u)
+	}
+	return updates
+}
+
+// pairUpdateResults sets the response of each operation to the first unpaired UpdateResult
+// with the same operation and path. Origins are only compared if both are set,
+// since targets may omit them in the response.
+func pairUpdateResults(ops []*OperationResult, updates []*gpb.UpdateResult) []*OperationResult {
+	paired := make([]bool, len(updates))
+	for _, op := range ops {
+		for i, u := range updates {
+			if paired[i] || u.GetOp() != op.Op {
+				continue
+			}
+			if o := u.GetPath().GetOrigin(); o != "" && op.Path.GetOrigin() != "" && o != op.Path.GetOrigin() {
+				continue
+			}
+			if !proto.Equal(&gpb.Path{Elem: u.GetPath().GetElem()}, &gpb.Path{Elem: op.Path.GetElem()}) {
+				continue
+			}
+			op.Response = u
+			paired[i] = true
+			break
+		}
+	}
+	return ops
+}
+
 // Update updates the configuration at the given query path with the val.
 func Update[T any](ctx context.Context, c *Client, q ConfigQuery[T], val T, opts ...Option) (*Result, error) {
-	resp, path, err := set(ctx, c, q, val, updatePath, opts...)
+	res, path, err := set(ctx, c, q, val, updatePath, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("Update(t) at path %s: %w", path, err)
 	}
-	return responseToResult(resp), nil
+	return res, nil
 }
 
 // Replace replaces the configuration at the given query path with the val.
 func Replace[T any](ctx context.Context, c *Client, q ConfigQuery[T], val T, opts ...Option) (*Result, error) {
-	resp, path, err := set(ctx, c, q, val, replacePath, opts...)
+	res, path, err := set(ctx, c, q, val, replacePath, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("Replace(t) at path %s: %w", path, err)
 	}
-	return responseToResult(resp), nil
+	return res, nil
 }
 
 // Delete deletes the configuration at the given query path.
 func Delete[T any](ctx context.Context, c *Client, q ConfigQuery[T], opts ...Option) (*Result, error) {
 	var t T
-	resp, path, err := set(ctx, c, q, t, deletePath, opts...)
+	res, path, err := set(ctx, c, q, t, deletePath, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("Delete(t) at path %s: %w", path, err)
 	}
-	return responseToResult(resp), nil
+	return res, nil
+}
+
+// UpdateAll updates the configuration at every existing path matching the wildcard config query with the val.
+// The matching paths are resolved with a ONCE subscription, then updated with a single SetRequest.
+// If the query is below a list entry, such as a leaf of the entry, there is a matching path for every existing entry,
+// even if the entry doesn't have a value at the path.
+// If match is not nil, only the paths whose current value it returns true for are updated.
+// It returns an error that wraps ErrNotPresent if no paths match.
+func UpdateAll[T any](ctx context.Context, c *Client, q WildcardQuery[T], val T, match func(*Value[T]) bool, opts ...Option) (*Result, error) {
+	res, err := setAll(ctx, c, q, val, updatePath, match, opts)
+	if err != nil {
+		return nil, fmt.Errorf("UpdateAll(t) at query %s: %w", q, err)
+	}
+	return res, nil
+}
+
+// DeleteAll deletes the configuration at every existing path matching the wildcard config query.
+// The matching paths are resolved with a ONCE subscription, then deleted with a single SetRequest.
+// If the query is below a list entry, such as a leaf of the entry, there is a matching path for every existing entry,
+// even if the entry doesn't have a value at the path.
+// If match is not nil, only the paths whose current value it returns true for are deleted.
+// It returns an error that wraps ErrNotPresent if no paths match.
+func DeleteAll[T any](ctx context.Context, c *Client, q WildcardQuery[T], match func(*Value[T]) bool, opts ...Option) (*Result, error) {
+	var t T
+	res, err := setAll(ctx, c, q, t, deletePath, match, opts)
+	if err != nil {
+		return nil, fmt.Errorf("DeleteAll(t) at query %s: %w", q, err)
+	}
+	return res, nil
+}
+
+// setAll applies the operation to every existing path matching the wildcard query in a single SetRequest.
+func setAll[T any](ctx context.Context, c *Client, q WildcardQuery[T], val T, op setOperation, match func(*Value[T]) bool, opts []Option) (*Result, error) {
+	if q.IsState() {
+		return nil, fmt.Errorf("query is a state query, only config queries can be set")
+	}
+	vals, err := lookupTargets(ctx, c, q, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lookup matching paths: %w", err)
+	}
+	var setVal interface{} = val
+	if q.isLeaf() && q.isScalar() {
+		setVal = &val
+	}
+	sb := &SetBatch{}
+	for _, v := range vals {
+		if match != nil && !match(v) {
+			continue
+		}
+		path := proto.Clone(v.Path).(*gpb.Path)
+		path.Target = ""
+		bop := &batchOp{
+			rawPath:      path,
+			mode:         op,
+			shadowpath:   q.isShadowPath(),
+			isLeaf:       q.isLeaf(),
+			compressInfo: q.compressInfo(),
+			query:        q,
+		}
+		if op != deletePath {
+			bop.val = setVal
+			bop.validate = validateFn[T](q, val)
+		}
+		sb.ops = append(sb.ops, bop)
+	}
+	if len(sb.ops) == 0 {
+		return nil, ErrNotPresent
+	}
+	return sb.Set(ctx, c, opts...)
+}
+
+// lookupTargets returns the current values of the paths matching the wildcard query that setAll applies to.
+// If the query is below the last list entry in its path, such as a leaf of the entry,
+// there is a path for every existing entry of the list, and the value isn't present if the entry doesn't have it.
+func lookupTargets[T any](ctx context.Context, c *Client, q WildcardQuery[T], opts []Option) ([]*Value[T], error) {
+	queryPath, err := resolvePath(q.PathStruct())
+	if err != nil {
+		return nil, err
+	}
+	last := len(queryPath.GetElem()) - 1
+	for last >= 0 && !hasWildcardKey(queryPath.GetElem()[last]) {
+		last--
+	}
+	if last == -1 || last == len(queryPath.GetElem())-1 {
+		// The paths are the list entries themselves, which exist only if they have values.
+		return LookupAll(ctx, c, q, opts...)
+	}
+
+	o := resolveClientOpts(c, opts)
+	entryPath := &gpb.Path{Origin: queryPath.GetOrigin(), Elem: queryPath.GetElem()[:last+1]}
+	sub, err := subscribePaths(ctx, c, q, []*gpb.Path{entryPath}, nil, gpb.SubscriptionList_ONCE, gpb.GetRequest_CONFIG, o)
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to list entries: %w", err)
+	}
+	data, err := receiveUntilSync(ctx, sub, false, entryPath, o)
+	if err != nil {
+		return nil, fmt.Errorf("failed to receive list entries: %w", err)
+	}
+	targets := map[string]*gpb.Path{}
+	var queryData []*DataPoint
+	for _, dp := range data {
+		if dp.Path == nil || util.PathMatchesQuery(dp.Path, queryPath) {
+			queryData = append(queryData, dp)
+		}
+		if len(dp.Path.GetElem()) <= last {
+			continue
+		}
+		target := &gpb.Path{
+			Origin: queryPath.GetOrigin(),
+			Elem:   append(slices.Clone(dp.Path.GetElem()[:last+1]), queryPath.GetElem()[last+1:]...),
+		}
+		targets[pathString(target)] = target
+	}
+	vals, err := unmarshalValues(queryData, q, o)
+	if err != nil {
+		return nil, err
+	}
+	present := map[string]*Value[T]{}
+	for _, v := range vals {
+		present[pathString(v.Path)] = v
+	}
+	keys := slices.Sorted(maps.Keys(targets))
+	vals = make([]*Value[T], 0, len(keys))
+	for _, k := range keys {
+		v, ok := present[k]
+		if !ok {
+			v = &Value[T]{Path: targets[k]}
+		}
+		vals = append(vals, v)
+	}
+	return vals, nil
+}
+
+// hasWildcardKey returns whether the element has a key matching any value.
+func hasWildcardKey(e *gpb.PathElem) bool {
+	for _, v := range e.GetKey() {
+		if v == "*" {
+			return true
+		}
+	}
+	return false
 }
 
 type batchOp struct {
@@ -746,6 +1289,14 @@ type batchOp struct {
 	shadowpath   bool
 	isLeaf       bool
 	compressInfo *CompressionInfo
+	// query is the query the operation was added with, nil if it was built from a gNMI path.
+	query UntypedQuery
+	// rawPath is the path of the operation if it was built from a gNMI path instead of a query, see Plan.
+	rawPath *gpb.Path
+	// snapshot adds the operation restoring the current config at the path to the batch, see SetWithRollback.
+	snapshot func(ctx context.Context, c *Client, rb *SetBatch, opts []Option) error
+	// validate validates the value of the operation, see WithSetValidation.
+	validate func(path *gpb.Path, opts []ygot.ValidationOption) *SetValidationErrors
 }
 
 // SetBatch allows multiple Set operations (Replace, Update, Delete) to be applied as part of a single Set transaction.
@@ -756,23 +1307,111 @@ type SetBatch struct {
 
 // Set performs the gnmi.Set request with all queued operations.
 func (sb *SetBatch) Set(ctx context.Context, c *Client, opts ...Option) (*Result, error) {
+	req, err := sb.request(ctx, c, opts)
+	if err != nil {
+		return nil, err
+	}
+	return c.sendSet(ctx, req, sb.operations(), opts)
+}
+
+// request builds the SetRequest sent by the client for the operations of the batch.
+func (sb *SetBatch) request(ctx context.Context, c *Client, opts []Option) (*gpb.SetRequest, error) {
+	opts, err := c.setOpts(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	req, err := sb.populate(opts)
+	if err != nil {
+		return nil, err
+	}
+	req.Prefix = &gpb.Path{
+		Target: c.target,
+	}
+	return req, nil
+}
+
+// populate returns a SetRequest without a prefix containing the operations of the batch.
+func (sb *SetBatch) populate(opts []Option) (*gpb.SetRequest, error) {
+	o := resolveOpts(opts)
 	req := &gpb.SetRequest{}
+	validateErrs := &SetValidationErrors{}
 	for _, op := range sb.ops {
-		path, err := resolvePath(op.path)
+		path, err := op.gnmiPath()
 		if err != nil {
 			return nil, err
 		}
+		if o.validateSet && op.validate != nil {
+			validateErrs.add(op.validate(path, o.validationOpts))
+		}
 		if err := populateSetRequest(req, path, op.val, op.mode, op.shadowpath, op.isLeaf, op.compressInfo, opts...); err != nil {
 			return nil, err
 		}
 	}
-	req.Prefix = &gpb.Path{
-		Target: c.target,
+	if !validateErrs.empty() {
+		return nil, validateErrs
+	}
+	return req, nil
+}
+
+// operations returns the operations of the batch, without their responses.
+func (sb *SetBatch) operations() []*OperationResult {
+	var ops []*OperationResult
+	for _, op := range sb.ops {
+		// The paths were already resolved when building the request.
+		path, _ := op.gnmiPath()
+		ops = append(ops, &OperationResult{Path: path, Op: op.mode.updateResultOp(), Query: op.query})
 	}
-	logutil.LogByLine(c.requestLogLevel, prettySetRequest(req))
-	resp, err := c.gnmiC.Set(ctx, req)
-	log.V(c.requestLogLevel).Infof("SetResponse:\n%s", prototext.Format(resp))
-	return responseToResult(resp), err
+	return ops
+}
+
+// gnmiPath returns the path of the operation.
+func (op *batchOp) gnmiPath() (*gpb.Path, error) {
+	if op.rawPath != nil {
+		return op.rawPath, nil
+	}
+	return resolvePath(op.path)
+}
+
+// sendSet sends the SetRequest, split according to the options, and returns the result of the operations.
+// The requests are sent in order, stopping at the first failure, and the result contains the last response.
+func (c *Client) sendSet(ctx context.Context, req *gpb.SetRequest, ops []*OperationResult, opts []Option) (_ *Result, rerr error) {
+	ctx, span := c.telemetry.startSet(ctx, ops)
+	defer func() { endSpan(span, outcomeOf(rerr), rerr) }()
+	reqs, err := resolveOpts(opts).split.split(req)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	info := &RPCInfo{Method: "Set", Operations: ops}
+	if len(ops) == 1 {
+		info.Query = ops[0].Query
+	}
+	var resp *gpb.SetResponse
+	var updates []*gpb.UpdateResult
+	for i, r := range reqs {
+		resp, err = c.sendSetRequest(ctx, info, r)
+		updates = append(updates, responseUpdates(resp)...)
+		if err != nil {
+			if len(reqs) > 1 {
+				err = fmt.Errorf("SetRequest %d of %d failed, the previous requests were applied: %w", i+1, len(reqs), err)
+			}
+			break
+		}
+	}
+	res := responseToResult(resp)
+	res.Duration = time.Since(start)
+	res.Operations = pairUpdateResults(ops, updates)
+	return res, err
+}
+
+// sendSetRequest sends the SetRequest through the interceptors, logging the request and the response.
+func (c *Client) sendSetRequest(ctx context.Context, info *RPCInfo, req *gpb.SetRequest) (*gpb.SetResponse, error) {
+	return unaryResponse[*gpb.SetResponse](c.invokeUnary(ctx, info, req, func(ctx context.Context, req proto.Message) (proto.Message, error) {
+		c.log.request(ctx, "Set", req)
+		resp, err := unaryInvoker(c.gnmiC.Set)(ctx, req)
+		c.log.request(ctx, "Set", resp)
+		return resp, err
+	}))
 }
 
 // BatchUpdate stores an update operation in the SetBatch.
@@ -785,9 +1424,12 @@ func BatchUpdate[T any](sb *SetBatch, q ConfigQuery[T], val T) {
 		path:         q.PathStruct(),
 		val:          setVal,
 		mode:         updatePath,
+		query:        q,
 		shadowpath:   q.isShadowPath(),
 		isLeaf:       q.isLeaf(),
 		compressInfo: q.compressInfo(),
+		snapshot:     snapshotFn(q),
+		validate:     validateFn(q, val),
 	})
 }
 
@@ -801,9 +1443,12 @@ func BatchReplace[T any](sb *SetBatch, q ConfigQuery[T], val T) {
 		path:         q.PathStruct(),
 		val:          setVal,
 		mode:         replacePath,
+		query:        q,
 		shadowpath:   q.isShadowPath(),
 		isLeaf:       q.isLeaf(),
 		compressInfo: q.compressInfo(),
+		snapshot:     snapshotFn(q),
+		validate:     validateFn(q, val),
 	})
 }
 
@@ -819,9 +1464,11 @@ func BatchUnionReplace[T any](sb *SetBatch, q ConfigQuery[T], val T) {
 		path:         q.PathStruct(),
 		val:          setVal,
 		mode:         unionreplacePath,
+		query:        q,
 		shadowpath:   q.isShadowPath(),
 		isLeaf:       q.isLeaf(),
 		compressInfo: q.compressInfo(),
+		validate:     validateFn(q, val),
 	})
 }
 
@@ -850,12 +1497,81 @@ func BatchDelete[T any](sb *SetBatch, q ConfigQuery[T]) {
 	sb.ops = append(sb.ops, &batchOp{
 		path:         q.PathStruct(),
 		mode:         deletePath,
+		query:        q,
 		shadowpath:   q.isShadowPath(),
 		isLeaf:       q.isLeaf(),
 		compressInfo: q.compressInfo(),
+		snapshot:     snapshotFn(q),
 	})
 }
 
+// SubscriptionParams are the parameters of the subscription to the paths added to a batch
+// with AddPathsWithParams. They override the options of the operation for those paths,
+// zero values use the options instead.
+// Like the options, they are only relevant for STREAM subscriptions.
+type SubscriptionParams struct {
+	// Mode is the subscription mode, TARGET_DEFINED uses WithSubscriptionMode.
+	Mode gpb.SubscriptionMode
+	// SampleInterval is the interval between samples in SAMPLE mode, zero uses WithSampleInterval.
+	SampleInterval time.Duration
+	// HeartbeatInterval is the interval after which a value is sent, even if it is unchanged
+	// or suppressed, in ON_CHANGE or SAMPLE mode.
+	HeartbeatInterval time.Duration
+	// SuppressRedundant suppresses sending unchanged values in SAMPLE mode.
+	SuppressRedundant bool
+}
+
+// apply sets the parameters on the subscription.
+func (p *SubscriptionParams) apply(sub *gpb.Subscription) {
+	if p.Mode != gpb.SubscriptionMode_TARGET_DEFINED {
+		sub.Mode = p.Mode
+	}
+	if p.SampleInterval != 0 {
+		sub.SampleInterval = uint64(p.SampleInterval.Nanoseconds())
+	}
+	sub.HeartbeatInterval = uint64(p.HeartbeatInterval.Nanoseconds())
+	sub.SuppressRedundant = p.SuppressRedundant
+}
+
+// paramsPath is a path added to a batch with subscription parameters.
+type paramsPath struct {
+	PathStruct
+	params *SubscriptionParams
+}
+
+// pathParams returns the subscription parameters of the path, or nil if there are none.
+func pathParams(ps PathStruct) *SubscriptionParams {
+	if pp, ok := ps.(*paramsPath); ok {
+		return pp.params
+	}
+	return nil
+}
+
+// addBatchPaths checks that the paths are children of the root and returns their path structs.
+// If params is not nil, the path structs contain the subscription parameters.
+func addBatchPaths(rootPS PathStruct, params *SubscriptionParams, paths []UntypedQuery) ([]PathStruct, error) {
+	root, err := resolvePath(rootPS)
+	if err != nil {
+		return nil, err
+	}
+	var pathstructs []PathStruct
+	for _, path := range paths {
+		ps := path.PathStruct()
+		p, err := resolvePath(ps)
+		if err != nil {
+			return nil, err
+		}
+		if !util.PathMatchesQuery(p, root) {
+			return nil, fmt.Errorf("root path %v is not a prefix of %v", root, p)
+		}
+		if params != nil {
+			ps = &paramsPath{PathStruct: ps, params: params}
+		}
+		pathstructs = append(pathstructs, ps)
+	}
+	return pathstructs, nil
+}
+
 // Batch contains a collection of paths.
 // Calling State() or Config() on the batch returns a query
 // that can be used to Lookup, Watch, etc on multiple paths at once.
@@ -873,21 +1589,20 @@ func NewBatch[T any](root SingletonQuery[T]) *Batch[T] {
 
 // AddPaths adds the paths to the batch. Paths must be children of the root.
 func (b *Batch[T]) AddPaths(paths ...UntypedQuery) error {
-	root, err := resolvePath(b.root.PathStruct())
+	pathstructs, err := addBatchPaths(b.root.PathStruct(), nil, paths)
 	if err != nil {
 		return err
 	}
-	var pathstructs []PathStruct
-	for _, path := range paths {
-		ps := path.PathStruct()
-		pathstructs = append(pathstructs, ps)
-		p, err := resolvePath(ps)
-		if err != nil {
-			return err
-		}
-		if !util.PathMatchesQuery(p, root) {
-			return fmt.Errorf("root path %v is not a prefix of %v", root, p)
-		}
+	b.paths = append(b.paths, pathstructs...)
+	return nil
+}
+
+// AddPathsWithParams adds the paths to the batch, subscribing to them with the parameters.
+// Paths must be children of the root.
+func (b *Batch[T]) AddPathsWithParams(params SubscriptionParams, paths ...UntypedQuery) error {
+	pathstructs, err := addBatchPaths(b.root.PathStruct(), &params, paths)
+	if err != nil {
+		return err
 	}
 	b.paths = append(b.paths, pathstructs...)
 	return nil
@@ -932,21 +1647,20 @@ func NewWildcardBatch[T any](root WildcardQuery[T]) *WildcardBatch[T] {
 
 // AddPaths adds the paths to the batch. Paths must be children of the root.
 func (b *WildcardBatch[T]) AddPaths(paths ...UntypedQuery) error {
-	root, err := resolvePath(b.root.PathStruct())
+	pathstructs, err := addBatchPaths(b.root.PathStruct(), nil, paths)
 	if err != nil {
 		return err
 	}
-	var pathstructs []PathStruct
-	for _, path := range paths {
-		ps := path.PathStruct()
-		pathstructs = append(pathstructs, ps)
-		p, err := resolvePath(ps)
-		if err != nil {
-			return err
-		}
-		if !util.PathMatchesQuery(p, root) {
-			return fmt.Errorf("root path %v is not a prefix of %v", root, p)
-		}
+	b.paths = append(b.paths, pathstructs...)
+	return nil
+}
+
+// AddPathsWithParams adds the paths to the batch, subscribing to them with the parameters.
+// Paths must be children of the root.
+func (b *WildcardBatch[T]) AddPathsWithParams(params SubscriptionParams, paths ...UntypedQuery) error {
+	pathstructs, err := addBatchPaths(b.root.PathStruct(), &params, paths)
+	if err != nil {
+		return err
 	}
 	b.paths = append(b.paths, pathstructs...)
 	return nil
@@ -1044,7 +1758,7 @@ func (r *Reconciler[T]) Start(ctx context.Context, fn func(cfg *Value[T], state
 	var cancel context.CancelFunc
 	ctx, cancel = context.WithCancel(ctx)
 
-	resolvedOpts := resolveOpts(r.opts)
+	resolvedOpts := resolveClientOpts(r.c, r.opts)
 	sub, err := subscribe(ctx, r.c, r.rootCfg, gpb.SubscriptionList_STREAM, resolvedOpts)
 	if err != nil {
 		cancel()
@@ -1052,7 +1766,7 @@ func (r *Reconciler[T]) Start(ctx context.Context, fn func(cfg *Value[T], state
 		return
 	}
 
-	dataCh, errCh := receiveStream(ctx, sub, r.rootCfg, resolvedOpts)
+	dataCh, errCh := receiveStream(ctx, r.c, sub, r.rootCfg, resolvedOpts)
 	go func() {
 		defer cancel()
 		// Create an intially empty GoStruct, into which all received datapoints will be unmarshalled.
@@ -1114,6 +1828,7 @@ func (r *Reconciler[T]) Start(ctx context.Context, fn func(cfg *Value[T], state
 							Timestamp:        cfgVal.Timestamp,
 							RecvTimestamp:    cfgVal.RecvTimestamp,
 							ComplianceErrors: cfgVal.ComplianceErrors,
+							redactor:         cfgVal.redactor,
 						}
 						stateVal := &Value[T]{
 							val:              stateVal.val,
@@ -1122,16 +1837,14 @@ func (r *Reconciler[T]) Start(ctx context.Context, fn func(cfg *Value[T], state
 							Timestamp:        stateVal.Timestamp,
 							RecvTimestamp:    stateVal.RecvTimestamp,
 							ComplianceErrors: stateVal.ComplianceErrors,
+							redactor:         stateVal.redactor,
 						}
 
 						delete(statePoints, cfgToStatePaths[cfgPath])
 
-						err := sr.fn(cfgVal, stateVal)
-						if errors.Is(err, ReconcilerAbortErr) {
+						if err := r.callback(ctx, sr.fn, cfgVal, stateVal); errors.Is(err, ReconcilerAbortErr) {
 							r.errCh <- err
 							return
-						} else if err != nil {
-							log.Warningf("reconciler error: %v", err)
 						}
 					}
 					for _, statePoint := range statePoints {
@@ -1145,6 +1858,7 @@ func (r *Reconciler[T]) Start(ctx context.Context, fn func(cfg *Value[T], state
 							Timestamp:        stateVal.Timestamp,
 							RecvTimestamp:    stateVal.RecvTimestamp,
 							ComplianceErrors: stateVal.ComplianceErrors,
+							redactor:         stateVal.redactor,
 						}
 						cfgVal := &Value[T]{
 							val:     cfgVal.val,
@@ -1155,22 +1869,17 @@ func (r *Reconciler[T]) Start(ctx context.Context, fn func(cfg *Value[T], state
 							Timestamp:        cfgVal.Timestamp,
 							RecvTimestamp:    cfgVal.RecvTimestamp,
 							ComplianceErrors: cfgVal.ComplianceErrors,
+							redactor:         cfgVal.redactor,
 						}
-						err := sr.fn(cfgVal, stateVal)
-						if errors.Is(err, ReconcilerAbortErr) {
+						if err := r.callback(ctx, sr.fn, cfgVal, stateVal); errors.Is(err, ReconcilerAbortErr) {
 							r.errCh <- err
 							return
-						} else if err != nil {
-							log.Warningf("reconciler error: %v", err)
 						}
 					}
 				}
-				err = fn(cfgVal, stateVal)
-				if errors.Is(err, ReconcilerAbortErr) {
+				if err := r.callback(ctx, fn, cfgVal, stateVal); errors.Is(err, ReconcilerAbortErr) {
 					r.errCh <- err
 					return
-				} else if err != nil {
-					log.Warningf("reconciler error: %v", err)
 				}
 			case err := <-errCh:
 				r.errCh <- err
@@ -1180,6 +1889,18 @@ func (r *Reconciler[T]) Start(ctx context.Context, fn func(cfg *Value[T], state
 	}()
 }
 
+// callback calls the callback function with the config and state values in a span,
+// and logs the errors other than ReconcilerAbortErr.
+func (r *Reconciler[T]) callback(ctx context.Context, fn func(cfg *Value[T], state *Value[T]) error, cfg, state *Value[T]) error {
+	_, span := r.c.telemetry.startPath(ctx, "Reconciler", cfg.Path)
+	err := fn(cfg, state)
+	endSpan(span, outcomeOf(err), err)
+	if err != nil && !errors.Is(err, ReconcilerAbortErr) {
+		r.c.log.warning(ctx, "reconciler error", "operation", "Reconciler", "path", pathString(cfg.Path), "error", err)
+	}
+	return err
+}
+
 // Await blocks until the reconciler exists.
 func (r *Reconciler[T]) Await() error {
 	return <-r.errCh
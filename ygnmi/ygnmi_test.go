@@ -3605,6 +3605,184 @@ func TestCollectAll(t *testing.T) {
 	}
 }
 
+func TestStream(t *testing.T) {
+	fakeGNMI, client := newClient(t)
+	path := testutil.GNMIPath(t, "/remote-container/state/a-leaf")
+	lq := exampleocpath.Root().RemoteContainer().ALeaf().State()
+	startTime := time.Now()
+
+	tests := []struct {
+		desc      string
+		stub      func(s *gnmitestutil.Stubber)
+		breakAt   int
+		wantVals  []*ygnmi.Value[string]
+		wantErr   string
+		wantSubTo *gpb.Path
+	}{{
+		desc: "break after second value",
+		stub: func(s *gnmitestutil.Stubber) {
+			s.Notification(&gpb.Notification{
+				Timestamp: startTime.UnixNano(),
+				Update: []*gpb.Update{{
+					Path: path,
+					Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "foo"}},
+				}},
+			}).Sync().Notification(&gpb.Notification{
+				Timestamp: startTime.Add(time.Millisecond).UnixNano(),
+				Update: []*gpb.Update{{
+					Path: path,
+					Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "bar"}},
+				}},
+			}).Notification(&gpb.Notification{
+				Timestamp: startTime.Add(2 * time.Millisecond).UnixNano(),
+				Update: []*gpb.Update{{
+					Path: path,
+					Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "baz"}},
+				}},
+			})
+		},
+		breakAt: 2,
+		wantVals: []*ygnmi.Value[string]{
+			(&ygnmi.Value[string]{
+				Timestamp: startTime,
+				Path:      path,
+			}).SetVal("foo"),
+			(&ygnmi.Value[string]{
+				Timestamp: startTime.Add(time.Millisecond),
+				Path:      path,
+			}).SetVal("bar"),
+		},
+	}, {
+		desc: "error ends iteration",
+		stub: func(s *gnmitestutil.Stubber) {
+			s.Notification(&gpb.Notification{
+				Timestamp: startTime.UnixNano(),
+				Update: []*gpb.Update{{
+					Path: path,
+					Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "foo"}},
+				}},
+			}).Sync()
+		},
+		wantVals: []*ygnmi.Value[string]{
+			(&ygnmi.Value[string]{
+				Timestamp: startTime,
+				Path:      path,
+			}).SetVal("foo"),
+		},
+		wantErr: "EOF",
+	}, {
+		desc: "delete",
+		stub: func(s *gnmitestutil.Stubber) {
+			s.Notification(&gpb.Notification{
+				Timestamp: startTime.UnixNano(),
+				Update: []*gpb.Update{{
+					Path: path,
+					Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "foo"}},
+				}},
+			}).Sync().Notification(&gpb.Notification{
+				Timestamp: startTime.Add(time.Millisecond).UnixNano(),
+				Delete:    []*gpb.Path{path},
+			})
+		},
+		breakAt: 2,
+		wantVals: []*ygnmi.Value[string]{
+			(&ygnmi.Value[string]{
+				Timestamp: startTime,
+				Path:      path,
+			}).SetVal("foo"),
+			{
+				Timestamp: startTime.Add(time.Millisecond),
+				Path:      path,
+			},
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			tt.stub(fakeGNMI.Stub())
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			var gotVals []*ygnmi.Value[string]
+			var gotErr error
+			for v, err := range ygnmi.Stream(ctx, client, lq) {
+				if err != nil {
+					gotErr = err
+					break
+				}
+				gotVals = append(gotVals, v)
+				if len(gotVals) == tt.breakAt {
+					break
+				}
+			}
+			if diff := errdiff.Substring(gotErr, tt.wantErr); diff != "" {
+				t.Fatalf("Stream() returned unexpected diff: %s", diff)
+			}
+			verifySubscriptionPathsSent(t, fakeGNMI, path)
+			if diff := cmp.Diff(tt.wantVals, gotVals, cmpopts.IgnoreFields(ygnmi.Value[string]{}, "RecvTimestamp"), cmp.AllowUnexported(ygnmi.Value[string]{}), protocmp.Transform()); diff != "" {
+				t.Errorf("Stream() returned unexpected values (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestStreamAll(t *testing.T) {
+	fakeGNMI, client := newClient(t)
+	leafQueryPath := testutil.GNMIPath(t, "model/a/single-key[key=*]/state/value")
+	key10Path := testutil.GNMIPath(t, "model/a/single-key[key=10]/state/value")
+	key11Path := testutil.GNMIPath(t, "model/a/single-key[key=11]/state/value")
+	startTime := time.Now()
+
+	fakeGNMI.Stub().Notification(&gpb.Notification{
+		Timestamp: startTime.UnixNano(),
+		Update: []*gpb.Update{{
+			Path: key10Path,
+			Val:  &gpb.TypedValue{Value: &gpb.TypedValue_IntVal{IntVal: 100}},
+		}, {
+			Path: key11Path,
+			Val:  &gpb.TypedValue{Value: &gpb.TypedValue_IntVal{IntVal: 101}},
+		}},
+	}).Sync().Notification(&gpb.Notification{
+		Timestamp: startTime.Add(time.Millisecond).UnixNano(),
+		Update: []*gpb.Update{{
+			Path: key10Path,
+			Val:  &gpb.TypedValue{Value: &gpb.TypedValue_IntVal{IntVal: 102}},
+		}},
+	})
+	wantVals := []*ygnmi.Value[int64]{
+		(&ygnmi.Value[int64]{
+			Timestamp: startTime,
+			Path:      key10Path,
+		}).SetVal(100),
+		(&ygnmi.Value[int64]{
+			Timestamp: startTime,
+			Path:      key11Path,
+		}).SetVal(101),
+		(&ygnmi.Value[int64]{
+			Timestamp: startTime.Add(time.Millisecond),
+			Path:      key10Path,
+		}).SetVal(102),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	var gotVals []*ygnmi.Value[int64]
+	for v, err := range ygnmi.StreamAll(ctx, client, exampleocpath.Root().Model().SingleKeyAny().Value().State()) {
+		if err != nil {
+			t.Fatalf("StreamAll() returned unexpected error: %v", err)
+		}
+		gotVals = append(gotVals, v)
+		if len(gotVals) == len(wantVals) {
+			break
+		}
+	}
+	verifySubscriptionPathsSent(t, fakeGNMI, leafQueryPath)
+	if diff := cmp.Diff(wantVals, gotVals, cmpopts.IgnoreFields(ygnmi.Value[int64]{}, "RecvTimestamp"), cmp.AllowUnexported(ygnmi.Value[int64]{}), protocmp.Transform(), cmpopts.SortSlices(func(a, b *ygnmi.Value[int64]) bool {
+		return a.Timestamp.Before(b.Timestamp) || (a.Timestamp.Equal(b.Timestamp) && a.Path.String() < b.Path.String())
+	})); diff != "" {
+		t.Errorf("StreamAll() returned unexpected values (-want,+got):\n%s", diff)
+	}
+}
+
 func TestUpdate(t *testing.T) {
 	setClient := &gnmitestutil.SetClient{}
 	client, err := ygnmi.NewClient(setClient, ygnmi.WithTarget("dut"))
@@ -3989,7 +4167,7 @@ func TestUpdate(t *testing.T) {
 				RawResponse: tt.stubResponse,
 				Timestamp:   time.Unix(0, tt.stubResponse.GetTimestamp()),
 			}
-			if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+			if diff := cmp.Diff(want, got, protocmp.Transform(), cmpopts.IgnoreFields(ygnmi.Result{}, "Operations", "Duration")); diff != "" {
 				t.Errorf("Update() returned unexpected value (-want,+got):\n%s", diff)
 			}
 		})
@@ -4839,6 +5017,111 @@ func TestCustomRootWildcardBatch(t *testing.T) {
 	}
 }
 
+func TestBatchSubscriptionParams(t *testing.T) {
+	fakeGNMI, c := newClient(t)
+	onePath := testutil.GNMIPath(t, "/parent/child/state/one")
+	twoPath := testutil.GNMIPath(t, "/parent/child/state/two")
+	valuePathWild := testutil.GNMIPath(t, "/model/a/single-key[key=*]/state/value")
+	keyPathWild := testutil.GNMIPath(t, "/model/a/single-key[key=*]/state/key")
+
+	// verifySubscriptionsSent verifies the subscriptions of the sent subscription request, ignoring the origin of the paths.
+	verifySubscriptionsSent := func(t *testing.T, want []*gpb.Subscription) {
+		t.Helper()
+		requests := fakeGNMI.Requests()
+		if len(requests) != 1 {
+			t.Fatalf("Number of subscription requests sent is not 1: %v", requests)
+		}
+		if diff := cmp.Diff(want, requests[0].GetSubscribe().GetSubscription(), protocmp.Transform(), protocmp.IgnoreFields(&gpb.Path{}, "origin")); diff != "" {
+			t.Errorf("Subscriptions (-want, +got):\n%s", diff)
+		}
+	}
+
+	t.Run("singleton", func(t *testing.T) {
+		fakeGNMI.Stub().Notification(&gpb.Notification{
+			Timestamp: 100,
+			Update: []*gpb.Update{{
+				Path: onePath,
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "foo"}},
+			}, {
+				Path: twoPath,
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "bar"}},
+			}},
+		}).Sync()
+		b := ygnmi.NewBatch(exampleocpath.Root().Parent().State())
+		if err := b.AddPaths(exampleocpath.Root().Parent().Child().One().State()); err != nil {
+			t.Fatal(err)
+		}
+		if err := b.AddPathsWithParams(ygnmi.SubscriptionParams{
+			Mode:              gpb.SubscriptionMode_SAMPLE,
+			SampleInterval:    10 * time.Second,
+			SuppressRedundant: true,
+		}, exampleocpath.Root().Parent().Child().Two().State()); err != nil {
+			t.Fatal(err)
+		}
+		_, err := ygnmi.Watch(context.Background(), c, b.Query(), func(v *ygnmi.Value[*exampleoc.Parent]) error {
+			if v, ok := v.Val(); ok && v.GetChild().GetOne() == "foo" && v.GetChild().GetTwo() == "bar" {
+				return nil
+			}
+			return ygnmi.Continue
+		}, ygnmi.WithSubscriptionMode(gpb.SubscriptionMode_ON_CHANGE)).Await()
+		if err != nil {
+			t.Fatalf("Watch() returned unexpected error: %v", err)
+		}
+		verifySubscriptionsSent(t, []*gpb.Subscription{{
+			Path: onePath,
+			Mode: gpb.SubscriptionMode_ON_CHANGE,
+		}, {
+			Path:              twoPath,
+			Mode:              gpb.SubscriptionMode_SAMPLE,
+			SampleInterval:    uint64(10 * time.Second),
+			SuppressRedundant: true,
+		}})
+	})
+	t.Run("wildcard", func(t *testing.T) {
+		fakeGNMI.Stub().Notification(&gpb.Notification{
+			Timestamp: 100,
+			Update: []*gpb.Update{{
+				Path: testutil.GNMIPath(t, "/model/a/single-key[key=foo]/state/value"),
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_IntVal{IntVal: 42}},
+			}},
+		}).Sync()
+		b := ygnmi.NewWildcardBatch(exampleocpath.Root().Model().SingleKeyAny().State())
+		if err := b.AddPathsWithParams(ygnmi.SubscriptionParams{
+			Mode:              gpb.SubscriptionMode_ON_CHANGE,
+			HeartbeatInterval: time.Minute,
+		}, exampleocpath.Root().Model().SingleKeyAny().Key().State()); err != nil {
+			t.Fatal(err)
+		}
+		if err := b.AddPathsWithParams(ygnmi.SubscriptionParams{
+			SampleInterval: time.Second,
+		}, exampleocpath.Root().Model().SingleKeyAny().Value().State()); err != nil {
+			t.Fatal(err)
+		}
+		_, err := ygnmi.WatchAll(context.Background(), c, b.Query(), func(v *ygnmi.Value[*exampleoc.Model_SingleKey]) error {
+			return nil
+		}, ygnmi.WithSubscriptionMode(gpb.SubscriptionMode_SAMPLE)).Await()
+		if err != nil {
+			t.Fatalf("WatchAll() returned unexpected error: %v", err)
+		}
+		verifySubscriptionsSent(t, []*gpb.Subscription{{
+			Path:              keyPathWild,
+			Mode:              gpb.SubscriptionMode_ON_CHANGE,
+			HeartbeatInterval: uint64(time.Minute),
+		}, {
+			Path:           valuePathWild,
+			Mode:           gpb.SubscriptionMode_SAMPLE,
+			SampleInterval: uint64(time.Second),
+		}})
+	})
+	t.Run("not prefix", func(t *testing.T) {
+		b := ygnmi.NewBatch(exampleocpath.Root().Parent().State())
+		err := b.AddPathsWithParams(ygnmi.SubscriptionParams{}, exampleocpath.Root().Model().State())
+		if diff := errdiff.Substring(err, "is not a prefix"); diff != "" {
+			t.Errorf("AddPathsWithParams returned unexpected diff: %s", diff)
+		}
+	})
+}
+
 func TestSetBatch(t *testing.T) {
 	setClient := &gnmitestutil.SetClient{}
 	client, err := ygnmi.NewClient(setClient, ygnmi.WithTarget("dut"))
@@ -5109,7 +5392,7 @@ func TestSetBatch(t *testing.T) {
 				RawResponse: tt.stubResponse,
 				Timestamp:   time.Unix(0, tt.stubResponse.GetTimestamp()),
 			}
-			if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+			if diff := cmp.Diff(want, got, protocmp.Transform(), cmpopts.IgnoreFields(ygnmi.Result{}, "Operations", "Duration")); diff != "" {
 				t.Errorf("Set() returned unexpected value (-want,+got):\n%s", diff)
 			}
 		})
@@ -5147,6 +5430,132 @@ func TestWatchCancel(t *testing.T) {
 	}
 }
 
+func TestWatchResubscribe(t *testing.T) {
+	onePath := testutil.GNMIPath(t, "/parent/child/state/one")
+	twoPath := testutil.GNMIPath(t, "/parent/child/state/two")
+	update := func(ts int64, path *gpb.Path, val string) *gpb.SubscribeResponse {
+		return &gpb.SubscribeResponse{Response: &gpb.SubscribeResponse_Update{Update: &gpb.Notification{
+			Timestamp: ts,
+			Update: []*gpb.Update{{
+				Path: path,
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: val}},
+			}},
+		}}}
+	}
+	sync := &gpb.SubscribeResponse{Response: &gpb.SubscribeResponse_SyncResponse{SyncResponse: true}}
+	unavailable := status.Error(codes.Unavailable, "stream reset")
+	policy := &ygnmi.ResubscribePolicy{InitialBackoff: time.Millisecond}
+
+	tests := []struct {
+		desc         string
+		streams      [][]*gpb.SubscribeResponse
+		errs         []error
+		opts         []ygnmi.Option
+		wantVals     []*exampleoc.Parent_Child
+		wantErr      string
+		wantRequests int
+	}{{
+		desc: "no resubscribe",
+		streams: [][]*gpb.SubscribeResponse{
+			{update(100, onePath, "foo"), sync},
+		},
+		errs:         []error{unavailable},
+		wantVals:     []*exampleoc.Parent_Child{{One: ygot.String("foo")}},
+		wantErr:      "stream reset",
+		wantRequests: 1,
+	}, {
+		desc: "resubscribe and delete stale paths",
+		streams: [][]*gpb.SubscribeResponse{
+			{update(100, onePath, "foo"), update(100, twoPath, "bar"), sync},
+			{update(101, onePath, "foo2"), sync},
+			{update(102, onePath, "done"), sync},
+		},
+		errs: []error{unavailable, unavailable, nil},
+		opts: []ygnmi.Option{ygnmi.WithResubscribe(policy)},
+		wantVals: []*exampleoc.Parent_Child{
+			{One: ygot.String("foo"), Two: ygot.String("bar")},
+			{One: ygot.String("foo2")},
+			{One: ygot.String("done")},
+		},
+		wantRequests: 3,
+	}, {
+		desc: "resubscribe before initial sync",
+		streams: [][]*gpb.SubscribeResponse{
+			{update(100, onePath, "foo")},
+			{update(101, onePath, "done"), update(101, twoPath, "bar"), sync},
+		},
+		errs: []error{unavailable, nil},
+		opts: []ygnmi.Option{ygnmi.WithResubscribe(policy)},
+		wantVals: []*exampleoc.Parent_Child{
+			{One: ygot.String("done"), Two: ygot.String("bar")},
+		},
+		wantRequests: 2,
+	}, {
+		desc: "max attempts exceeded",
+		streams: [][]*gpb.SubscribeResponse{
+			{update(100, onePath, "foo"), sync},
+			{update(101, onePath, "foo2")},
+			{update(102, onePath, "foo3")},
+		},
+		errs:         []error{unavailable, unavailable, unavailable},
+		opts:         []ygnmi.Option{ygnmi.WithResubscribe(&ygnmi.ResubscribePolicy{InitialBackoff: time.Millisecond, MaxAttempts: 2})},
+		wantVals:     []*exampleoc.Parent_Child{{One: ygot.String("foo")}},
+		wantErr:      "giving up after 2 resubscribe attempts",
+		wantRequests: 3,
+	}, {
+		desc: "non-retryable error",
+		streams: [][]*gpb.SubscribeResponse{
+			{update(100, onePath, "foo"), sync},
+		},
+		errs: []error{status.Error(codes.PermissionDenied, "denied")},
+		opts: []ygnmi.Option{ygnmi.WithResubscribe(&ygnmi.ResubscribePolicy{
+			InitialBackoff: time.Millisecond,
+			Retryable: func(err error) bool {
+				return status.Code(err) == codes.Unavailable
+			},
+		})},
+		wantVals:     []*exampleoc.Parent_Child{{One: ygot.String("foo")}},
+		wantErr:      "denied",
+		wantRequests: 1,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			srv := &sequenceServer{streams: tt.streams, errs: tt.errs}
+			c := newServerClient(t, srv)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			var gotVals []*exampleoc.Parent_Child
+			w := ygnmi.Watch(ctx, c, exampleocpath.Root().Parent().Child().State(), func(v *ygnmi.Value[*exampleoc.Parent_Child]) error {
+				val, ok := v.Val()
+				if !ok {
+					return ygnmi.Continue
+				}
+				cp, err := ygot.DeepCopy(val)
+				if err != nil {
+					return err
+				}
+				gotVals = append(gotVals, cp.(*exampleoc.Parent_Child))
+				if val.GetOne() == "done" {
+					return nil
+				}
+				return ygnmi.Continue
+			}, tt.opts...)
+			_, err := w.Await()
+			if diff := errdiff.Substring(err, tt.wantErr); diff != "" {
+				t.Fatalf("Await() returned unexpected diff: %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantVals, gotVals); diff != "" {
+				t.Errorf("Watch() got unexpected values (-want,+got):\n%s", diff)
+			}
+			if got := len(srv.Requests()); got != tt.wantRequests {
+				t.Errorf("Watch() sent unexpected number of SubscribeRequests: got %d, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
 func TestReconcile(t *testing.T) {
 	fakeGNMI, c := newClient(t)
 	twoPath := testutil.GNMIPath(t, "/parent/child/state/two")
@@ -18,7 +18,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -32,6 +34,8 @@ import (
 	"github.com/openconfig/ygnmi/ygnmi"
 	"github.com/openconfig/ygot/util"
 	"github.com/openconfig/ygot/ygot"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/local"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/testing/protocmp"
 	"google.golang.org/protobuf/types/known/anypb"
@@ -53,7 +57,7 @@ func lookupCheckFn[T any](t *testing.T, fakeGNMI *gnmitestutil.FakeGNMI, c *ygnm
 	checkJustReceived(t, got.RecvTimestamp)
 	wantVal.RecvTimestamp = got.RecvTimestamp
 
-	if diff := cmp.Diff(wantVal, got, cmp.AllowUnexported(ygnmi.Value[T]{}, exampleoc.Model_SingleKey_OrderedList_OrderedMap{}, exampleocconfig.Model_SingleKey_OrderedList_OrderedMap{}), protocmp.Transform()); diff != "" {
+	if diff := cmp.Diff(wantVal, got, cmp.AllowUnexported(ygnmi.Value[T]{}, ygnmi.TelemetryError{}, exampleoc.Model_SingleKey_OrderedList_OrderedMap{}, exampleocconfig.Model_SingleKey_OrderedList_OrderedMap{}), protocmp.Transform()); diff != "" {
 		t.Errorf("Lookup(ctx, c, %v) returned unexpected diff (-want,+got):\n %s\nComplianceErrors:\n%v", inQuery, diff, got.ComplianceErrors)
 	}
 
@@ -73,7 +77,7 @@ func lookupWithGetCheckFn[T any](t *testing.T, fakeGNMI *gnmitestutil.FakeGNMI,
 	if err != nil {
 		return
 	}
-	copts := []cmp.Option{cmp.AllowUnexported(ygnmi.Value[T]{}, exampleoc.Model_SingleKey_OrderedList_OrderedMap{}, exampleocconfig.Model_SingleKey_OrderedList_OrderedMap{}), cmpopts.IgnoreFields(ygnmi.Value[T]{}, "RecvTimestamp"), protocmp.Transform()}
+	copts := []cmp.Option{cmp.AllowUnexported(ygnmi.Value[T]{}, ygnmi.TelemetryError{}, exampleoc.Model_SingleKey_OrderedList_OrderedMap{}, exampleocconfig.Model_SingleKey_OrderedList_OrderedMap{}), cmpopts.IgnoreFields(ygnmi.Value[T]{}, "RecvTimestamp"), protocmp.Transform()}
 	if nonLeaf {
 		copts = append(copts, cmpopts.IgnoreFields(ygnmi.TelemetryError{}, "Err"))
 	}
@@ -131,7 +135,7 @@ func watchCheckFn[T any](t *testing.T, fakeGNMI *gnmitestutil.FakeGNMI, duration
 		if i > len(wantVals) {
 			t.Fatalf("Predicate(%d) expected no more values but got: %+v", i, v)
 		}
-		if diff := cmp.Diff(wantVals[i], v, extractErrorMessage, cmpopts.IgnoreFields(ygnmi.Value[T]{}, "RecvTimestamp", "Timestamp"), cmp.AllowUnexported(ygnmi.Value[T]{}, exampleoc.Model_SingleKey_OrderedList_OrderedMap{}, exampleocconfig.Model_SingleKey_OrderedList_OrderedMap{}), protocmp.Transform()); diff != "" {
+		if diff := cmp.Diff(wantVals[i], v, extractErrorMessage, cmpopts.IgnoreFields(ygnmi.Value[T]{}, "RecvTimestamp", "Timestamp"), cmp.AllowUnexported(ygnmi.Value[T]{}, ygnmi.TelemetryError{}, exampleoc.Model_SingleKey_OrderedList_OrderedMap{}, exampleocconfig.Model_SingleKey_OrderedList_OrderedMap{}), protocmp.Transform()); diff != "" {
 			t.Errorf("Predicate(%d) got unexpected input (-want,+got):\n %s\nComplianceErrors:\n%v", i, diff, v.ComplianceErrors)
 		}
 		val, present := v.Val()
@@ -158,7 +162,7 @@ func watchCheckFn[T any](t *testing.T, fakeGNMI *gnmitestutil.FakeGNMI, duration
 		checkJustReceived(t, val.RecvTimestamp)
 		wantLastVal.RecvTimestamp = val.RecvTimestamp
 	}
-	if diff := cmp.Diff(wantLastVal, val, extractErrorMessage, cmp.AllowUnexported(ygnmi.Value[T]{}, exampleoc.Model_SingleKey_OrderedList_OrderedMap{}, exampleocconfig.Model_SingleKey_OrderedList_OrderedMap{}), protocmp.Transform()); diff != "" {
+	if diff := cmp.Diff(wantLastVal, val, extractErrorMessage, cmp.AllowUnexported(ygnmi.Value[T]{}, ygnmi.TelemetryError{}, exampleoc.Model_SingleKey_OrderedList_OrderedMap{}, exampleocconfig.Model_SingleKey_OrderedList_OrderedMap{}), protocmp.Transform()); diff != "" {
 		t.Errorf("Await() returned unexpected value (-want,+got):\n%s", diff)
 	}
 
@@ -179,7 +183,7 @@ func collectCheckFn[T any](t *testing.T, fakeGNMI *gnmitestutil.FakeGNMI, c *ygn
 	for _, val := range vals {
 		checkJustReceived(t, val.RecvTimestamp)
 	}
-	if diff := cmp.Diff(wantVals, vals, cmpopts.IgnoreFields(ygnmi.Value[T]{}, "RecvTimestamp"), cmp.AllowUnexported(ygnmi.Value[T]{}), protocmp.Transform()); diff != "" {
+	if diff := cmp.Diff(wantVals, vals, cmpopts.IgnoreFields(ygnmi.Value[T]{}, "RecvTimestamp"), cmp.AllowUnexported(ygnmi.Value[T]{}, ygnmi.TelemetryError{}), protocmp.Transform()); diff != "" {
 		t.Errorf("Await() returned unexpected value (-want,+got):\n%s", diff)
 	}
 
@@ -204,7 +208,7 @@ func lookupAllCheckFn[T any](t *testing.T, fakeGNMI *gnmitestutil.FakeGNMI, c *y
 		checkJustReceived(t, val.RecvTimestamp)
 	}
 	copts := []cmp.Option{
-		cmp.AllowUnexported(ygnmi.Value[T]{}, exampleoc.Model_SingleKey_OrderedList_OrderedMap{}, exampleocconfig.Model_SingleKey_OrderedList_OrderedMap{}),
+		cmp.AllowUnexported(ygnmi.Value[T]{}, ygnmi.TelemetryError{}, exampleoc.Model_SingleKey_OrderedList_OrderedMap{}, exampleocconfig.Model_SingleKey_OrderedList_OrderedMap{}),
 		cmpopts.IgnoreFields(ygnmi.Value[T]{}, "RecvTimestamp"),
 		protocmp.Transform(),
 	}
@@ -241,7 +245,7 @@ func configCheckFn(t *testing.T, setClient *gnmitestutil.SetClient, c *ygnmi.Cli
 		RawResponse: stubResponse,
 		Timestamp:   time.Unix(0, stubResponse.GetTimestamp()),
 	}
-	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+	if diff := cmp.Diff(want, got, protocmp.Transform(), cmpopts.IgnoreFields(ygnmi.Result{}, "Operations", "Duration")); diff != "" {
 		t.Errorf("config operation returned unexpected value (-want,+got):\n%s", diff)
 	}
 }
@@ -374,6 +378,72 @@ func (g *gnmiS) Subscribe(srv gpb.GNMI_SubscribeServer) error {
 	return nil
 }
 
+// sequenceServer is a gNMI server that responds to the nth Subscribe RPC with the
+// nth list of responses, and then closes the stream with the nth error.
+// Subscribe RPCs beyond the configured streams are held open until cancelled.
+type sequenceServer struct {
+	gpb.UnimplementedGNMIServer
+	streams [][]*gpb.SubscribeResponse
+	errs    []error
+
+	mu       sync.Mutex
+	requests []*gpb.SubscribeRequest
+}
+
+func (s *sequenceServer) Subscribe(srv gpb.GNMI_SubscribeServer) error {
+	req, err := srv.Recv()
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	i := len(s.requests)
+	s.requests = append(s.requests, req)
+	s.mu.Unlock()
+	if i >= len(s.streams) {
+		<-srv.Context().Done()
+		return nil
+	}
+	for _, resp := range s.streams[i] {
+		if err := srv.Send(resp); err != nil {
+			return err
+		}
+	}
+	return s.errs[i]
+}
+
+// Requests returns the SubscribeRequests received by the server.
+func (s *sequenceServer) Requests() []*gpb.SubscribeRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// newServerClient starts a gRPC server for the gNMI server and returns a client connected to it.
+func newServerClient(t testing.TB, srv gpb.GNMIServer, opts ...ygnmi.ClientOption) *ygnmi.Client {
+	t.Helper()
+	s := grpc.NewServer(grpc.Creds(local.NewCredentials()))
+	gpb.RegisterGNMIServer(s, srv)
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		//nolint:errcheck // Don't care about this error.
+		s.Serve(l)
+	}()
+	t.Cleanup(s.Stop)
+	conn, err := grpc.NewClient(l.Addr().String(), grpc.WithTransportCredentials(local.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	c, err := ygnmi.NewClient(gpb.NewGNMIClient(conn), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
 type fakeFT struct {
 	inPath  *gpb.Path
 	outPath *gpb.Path
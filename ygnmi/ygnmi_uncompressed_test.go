@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/openconfig/gnmi/errdiff"
 	"github.com/openconfig/ygnmi/internal/gnmitestutil"
 	"github.com/openconfig/ygnmi/internal/testutil"
@@ -622,7 +623,7 @@ func TestUncompressedSetBatch(t *testing.T) {
 				RawResponse: tt.stubResponse,
 				Timestamp:   time.Unix(0, tt.stubResponse.GetTimestamp()),
 			}
-			if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+			if diff := cmp.Diff(want, got, protocmp.Transform(), cmpopts.IgnoreFields(ygnmi.Result{}, "Operations", "Duration")); diff != "" {
 				t.Errorf("Set() returned unexpected value (-want,+got):\n%s", diff)
 			}
 		})